@@ -0,0 +1,4 @@
+data "gitlab_branches" "releases" {
+  project = "foo/bar/baz"
+  regex   = "^release/"
+}
@@ -0,0 +1,13 @@
+data "gitlab_ci_lint" "ci" {
+  project         = "12345"
+  content         = file("${path.module}/.gitlab-ci.yml")
+  fail_on_invalid = true
+}
+
+resource "gitlab_repository_file" "ci" {
+  project        = "12345"
+  file_path      = ".gitlab-ci.yml"
+  branch         = "main"
+  content        = base64encode(data.gitlab_ci_lint.ci.content)
+  commit_message = "Update CI configuration"
+}
@@ -0,0 +1,5 @@
+data "gitlab_project_environments" "review_apps" {
+  project           = "12345"
+  states            = "available"
+  environment_scope = "review/*"
+}
@@ -0,0 +1,3 @@
+data "gitlab_project_protected_tags" "example" {
+  project_id = "foo/bar/baz"
+}
@@ -0,0 +1,10 @@
+# The latest release of a project
+data "gitlab_release" "latest" {
+  project = "foo/bar"
+}
+
+# A specific release of a project
+data "gitlab_release" "v1_0_0" {
+  project  = "foo/bar"
+  tag_name = "v1.0.0"
+}
@@ -0,0 +1,6 @@
+data "gitlab_repository_file" "version" {
+  project   = "foo/bar/baz"
+  file_path = "VERSION"
+  ref       = "main"
+  encoding  = "text"
+}
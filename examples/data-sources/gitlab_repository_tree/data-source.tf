@@ -0,0 +1,14 @@
+data "gitlab_repository_tree" "environments" {
+  project = "foo/bar/baz"
+  ref     = "main"
+  path    = "environments"
+  type    = "tree"
+}
+
+data "gitlab_repository_tree" "terraform_files" {
+  project    = 30
+  ref        = "main"
+  recursive  = true
+  type       = "blob"
+  path_regex = "\\.tf$"
+}
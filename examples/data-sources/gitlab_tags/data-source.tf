@@ -0,0 +1,6 @@
+data "gitlab_tags" "versions" {
+  project  = "foo/bar/baz"
+  regex    = "^v\\d+\\.\\d+\\.\\d+$"
+  order_by = "updated"
+  sort     = "desc"
+}
@@ -0,0 +1,2 @@
+# Gitlab branches can be imported with a key composed of `<project_id>:<branch_name>`, e.g.
+terraform import gitlab_branch.example "12345:develop"
@@ -0,0 +1,11 @@
+resource "gitlab_project" "example" {
+  name                   = "example"
+  initialize_with_readme = true
+}
+
+resource "gitlab_branch" "develop" {
+  project         = gitlab_project.example.id
+  name            = "develop"
+  ref             = "main"
+  keep_on_destroy = true
+}
@@ -32,3 +32,38 @@ resource "gitlab_branch_protection" "main" {
     }
   }
 }
+
+# Example adopting the protection GitLab creates for the default branch of a new project
+resource "gitlab_branch_protection" "default_branch" {
+  project            = gitlab_project.example.id
+  branch             = gitlab_project.example.default_branch
+  push_access_level  = "no one"
+  merge_access_level = "maintainer"
+  adopt_existing     = true
+}
+
+# Example allowing force pushes and a deploy key to push
+resource "gitlab_branch_protection" "release" {
+  project                = "12345"
+  branch                 = "release"
+  push_access_level      = "maintainer"
+  merge_access_level     = "maintainer"
+  unprotect_access_level = "maintainer"
+  allow_force_push       = true
+
+  allowed_to_push {
+    deploy_key_id = 123
+  }
+}
+
+# Example protecting all release branches with a wildcard
+resource "gitlab_branch_protection" "releases" {
+  project            = "12345"
+  branch             = "release/*"
+  push_access_level  = "maintainer"
+  merge_access_level = "maintainer"
+}
+
+output "protected_release_branches" {
+  value = gitlab_branch_protection.releases.matching_branches
+}
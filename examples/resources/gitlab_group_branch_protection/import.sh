@@ -0,0 +1,2 @@
+# Gitlab group protected branches can be imported with a key composed of `<group_id>:<branch>`, e.g.
+terraform import gitlab_group_branch_protection.release "12345:release/*"
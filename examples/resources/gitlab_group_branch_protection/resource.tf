@@ -0,0 +1,10 @@
+resource "gitlab_group_branch_protection" "release" {
+  group              = "12345"
+  branch             = "release/*"
+  push_access_level  = "maintainer"
+  merge_access_level = "developer"
+
+  allowed_to_merge {
+    user_id = 15
+  }
+}
@@ -0,0 +1,2 @@
+# Gitlab group protected environments can be imported with a key composed of `<group_id>:<environment>`, e.g.
+terraform import gitlab_group_protected_environment.production "12345:production"
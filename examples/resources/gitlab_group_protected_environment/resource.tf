@@ -0,0 +1,12 @@
+resource "gitlab_group_protected_environment" "production" {
+  group       = "12345"
+  environment = "production"
+
+  deploy_access_levels {
+    access_level = "maintainer"
+  }
+
+  approval_rules {
+    access_level = "maintainer"
+  }
+}
@@ -0,0 +1,23 @@
+resource "gitlab_pipeline_trigger" "bootstrap" {
+  project     = "12345"
+  description = "Bootstrap infrastructure"
+}
+
+resource "gitlab_pipeline_run" "bootstrap" {
+  project             = "12345"
+  ref                 = "main"
+  trigger_token       = gitlab_pipeline_trigger.bootstrap.token
+  wait_for_completion = true
+
+  variables = {
+    CLUSTER_ENDPOINT = "https://cluster.example.com"
+  }
+
+  triggers = {
+    cluster_id = "my-cluster"
+  }
+
+  timeouts {
+    create = "1h"
+  }
+}
@@ -4,3 +4,16 @@ resource "gitlab_pipeline_schedule" "example" {
   ref         = "master"
   cron        = "0 1 * * *"
 }
+
+resource "gitlab_pipeline_schedule" "nightly" {
+  project        = "12345"
+  description    = "Nightly deployment"
+  ref            = "main"
+  cron           = "0 2 * * *"
+  take_ownership = true
+
+  variables {
+    key   = "DEPLOY_TARGET"
+    value = "staging"
+  }
+}
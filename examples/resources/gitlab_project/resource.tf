@@ -16,3 +16,10 @@ resource "gitlab_project" "example-two" {
     prevent_secrets        = true
   }
 }
+
+# Project forked from a template project
+resource "gitlab_project" "example-fork" {
+  name                   = "example-fork"
+  namespace_id           = 42
+  forked_from_project_id = gitlab_project.example.id
+}
@@ -0,0 +1,2 @@
+# Gitlab environments can be imported with a key composed of `<project_id>:<environment_id>`, e.g.
+terraform import gitlab_project_environment.staging "12345:42"
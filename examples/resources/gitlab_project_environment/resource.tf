@@ -0,0 +1,14 @@
+resource "gitlab_project_environment" "staging" {
+  project             = "12345"
+  name                = "staging"
+  external_url        = "https://staging.example.com"
+  tier                = "staging"
+  stop_before_destroy = true
+}
+
+resource "gitlab_project_variable" "staging_url" {
+  project           = "12345"
+  key               = "DEPLOY_URL"
+  value             = gitlab_project_environment.staging.external_url
+  environment_scope = gitlab_project_environment.staging.name
+}
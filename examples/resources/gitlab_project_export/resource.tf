@@ -0,0 +1,9 @@
+resource "gitlab_project_export" "backup" {
+  project = "my-group/example"
+  path    = "${path.module}/backups/example.tar.gz"
+
+  # Create a fresh export whenever the project settings change
+  triggers = {
+    settings = sha1(jsonencode(gitlab_project.example))
+  }
+}
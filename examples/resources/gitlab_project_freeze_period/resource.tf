@@ -4,3 +4,7 @@ resource "gitlab_project_freeze_period" "schedule" {
   freeze_end    = "0 7 * * 1"
   cron_timezone = "UTC"
 }
+
+output "next_freeze_window" {
+  value = "${gitlab_project_freeze_period.schedule.next_freeze_start} - ${gitlab_project_freeze_period.schedule.next_freeze_end}"
+}
@@ -0,0 +1,10 @@
+resource "gitlab_project_import" "example" {
+  file      = "${path.module}/exports/example.tar.gz"
+  path      = "example"
+  namespace = "my-group"
+
+  override_params {
+    description      = "Imported from the old GitLab instance"
+    visibility_level = "private"
+  }
+}
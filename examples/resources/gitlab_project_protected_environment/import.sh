@@ -0,0 +1,2 @@
+# Gitlab protected environments can be imported with a key composed of `<project_id>:<environment>`, e.g.
+terraform import gitlab_project_protected_environment.production "12345:production"
@@ -0,0 +1,18 @@
+resource "gitlab_project_protected_environment" "production" {
+  project                 = "12345"
+  environment             = "production"
+  required_approval_count = 1
+
+  deploy_access_levels {
+    access_level = "maintainer"
+  }
+
+  deploy_access_levels {
+    user_id = 42
+  }
+
+  approval_rules {
+    group_id           = 7
+    required_approvals = 2
+  }
+}
@@ -0,0 +1,2 @@
+# Gitlab releases can be imported with a key composed of `<project_id>:<tag_name>`, e.g.
+terraform import gitlab_release.example "12345:v1.0.0"
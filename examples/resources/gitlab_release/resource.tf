@@ -0,0 +1,22 @@
+resource "gitlab_project" "example" {
+  name                   = "example"
+  initialize_with_readme = true
+}
+
+resource "gitlab_release" "v1_0_0" {
+  project     = gitlab_project.example.id
+  tag_name    = "v1.0.0"
+  ref         = "main"
+  name        = "Example v1.0.0"
+  description = "The first release of example"
+  milestones  = ["v1.0"]
+
+  assets {
+    links {
+      name      = "binary"
+      url       = "https://example.com/downloads/example-linux-amd64"
+      filepath  = "/bin/example-linux-amd64"
+      link_type = "package"
+    }
+  }
+}
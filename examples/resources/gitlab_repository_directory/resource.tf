@@ -0,0 +1,21 @@
+resource "gitlab_group" "this" {
+  name        = "example"
+  path        = "example"
+  description = "An example group"
+}
+resource "gitlab_project" "this" {
+  name                   = "example"
+  namespace_id           = gitlab_group.this.id
+  initialize_with_readme = true
+}
+resource "gitlab_repository_directory" "this" {
+  project        = gitlab_project.this.id
+  branch         = "main"
+  source_dir     = "${path.module}/skeleton"
+  target_path    = "ci"
+  include        = ["**/*.yml", "**/*.sh"]
+  exclude        = ["**/local-*"]
+  author_email   = "terraform@example.com"
+  author_name    = "Terraform"
+  commit_message = "chore: sync service skeleton"
+}
@@ -0,0 +1,2 @@
+# Gitlab tags can be imported with a key composed of `<project_id>:<tag_name>`, e.g.
+terraform import gitlab_tag.example "12345:v1.0.0"
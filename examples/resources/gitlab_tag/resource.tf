@@ -0,0 +1,19 @@
+resource "gitlab_project" "example" {
+  name                   = "example"
+  initialize_with_readme = true
+}
+
+# A lightweight tag
+resource "gitlab_tag" "v1_0_0" {
+  project = gitlab_project.example.id
+  name    = "v1.0.0"
+  ref     = "main"
+}
+
+# An annotated tag
+resource "gitlab_tag" "v1_1_0" {
+  project = gitlab_project.example.id
+  name    = "v1.1.0"
+  ref     = "main"
+  message = "Release v1.1.0"
+}
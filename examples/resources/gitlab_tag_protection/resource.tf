@@ -3,3 +3,16 @@ resource "gitlab_tag_protection" "TagProtect" {
   tag                 = "TagProtected"
   create_access_level = "developer"
 }
+
+resource "gitlab_tag_protection" "ReleaseTags" {
+  project             = "12345"
+  tag                 = "v*"
+  create_access_level = "maintainer"
+
+  allowed_to_create {
+    user_id = 42
+  }
+  allowed_to_create {
+    deploy_key_id = 7
+  }
+}
@@ -0,0 +1,588 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	// Embed the tz database, so that time zones can be validated on every platform.
+	_ "time/tzdata"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// cronSchedule is a parsed cron expression in the dialect GitLab uses for pipeline schedules and freeze periods:
+// five fields (minute, hour, day of month, month and day of week) with lists, ranges, steps and names,
+// or one of the @yearly, @annually, @monthly, @weekly, @daily, @midnight and @hourly shortcuts.
+// Like Fugit, which GitLab parses the expressions with, a leading seconds field, L for the last day of the month
+// and the nth weekday of the month (e.g. mon#2 or fri#L) are supported as well.
+type cronSchedule struct {
+	minute, hour, dayOfMonth, month, dayOfWeek uint64
+	lastDayOfMonth                             bool
+	nthDaysOfWeek                              []cronNthDayOfWeek
+	// Like in Vixie cron, a day matches either day field if both are restricted.
+	dayOfMonthStar, dayOfWeekStar bool
+}
+
+// cronNthDayOfWeek is the nth occurrence of a weekday in the month, where -1 is the last one.
+type cronNthDayOfWeek struct {
+	weekday time.Weekday
+	n       int
+}
+
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}},
+	// 7 is Sunday as well and folded into 0 after parsing.
+	{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}},
+}
+
+var cronShortcuts = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// activeSupportTimeZones maps the time zone names of Rails, which GitLab accepts besides the tz database names,
+// to the tz database.
+var activeSupportTimeZones = map[string]string{
+	"International Date Line West": "Etc/GMT+12",
+	"Midway Island":                "Pacific/Midway",
+	"American Samoa":               "Pacific/Pago_Pago",
+	"Hawaii":                       "Pacific/Honolulu",
+	"Alaska":                       "America/Juneau",
+	"Pacific Time (US & Canada)":   "America/Los_Angeles",
+	"Tijuana":                      "America/Tijuana",
+	"Mountain Time (US & Canada)":  "America/Denver",
+	"Arizona":                      "America/Phoenix",
+	"Chihuahua":                    "America/Chihuahua",
+	"Mazatlan":                     "America/Mazatlan",
+	"Central Time (US & Canada)":   "America/Chicago",
+	"Saskatchewan":                 "America/Regina",
+	"Guadalajara":                  "America/Mexico_City",
+	"Mexico City":                  "America/Mexico_City",
+	"Monterrey":                    "America/Monterrey",
+	"Central America":              "America/Guatemala",
+	"Eastern Time (US & Canada)":   "America/New_York",
+	"Indiana (East)":               "America/Indiana/Indianapolis",
+	"Bogota":                       "America/Bogota",
+	"Lima":                         "America/Lima",
+	"Quito":                        "America/Lima",
+	"Atlantic Time (Canada)":       "America/Halifax",
+	"Caracas":                      "America/Caracas",
+	"La Paz":                       "America/La_Paz",
+	"Santiago":                     "America/Santiago",
+	"Newfoundland":                 "America/St_Johns",
+	"Brasilia":                     "America/Sao_Paulo",
+	"Buenos Aires":                 "America/Argentina/Buenos_Aires",
+	"Montevideo":                   "America/Montevideo",
+	"Georgetown":                   "America/Guyana",
+	"Puerto Rico":                  "America/Puerto_Rico",
+	"Greenland":                    "America/Godthab",
+	"Mid-Atlantic":                 "Atlantic/South_Georgia",
+	"Azores":                       "Atlantic/Azores",
+	"Cape Verde Is.":               "Atlantic/Cape_Verde",
+	"Dublin":                       "Europe/Dublin",
+	"Edinburgh":                    "Europe/London",
+	"Lisbon":                       "Europe/Lisbon",
+	"London":                       "Europe/London",
+	"Casablanca":                   "Africa/Casablanca",
+	"Monrovia":                     "Africa/Monrovia",
+	"UTC":                          "Etc/UTC",
+	"Belgrade":                     "Europe/Belgrade",
+	"Bratislava":                   "Europe/Bratislava",
+	"Budapest":                     "Europe/Budapest",
+	"Ljubljana":                    "Europe/Ljubljana",
+	"Prague":                       "Europe/Prague",
+	"Sarajevo":                     "Europe/Sarajevo",
+	"Skopje":                       "Europe/Skopje",
+	"Warsaw":                       "Europe/Warsaw",
+	"Zagreb":                       "Europe/Zagreb",
+	"Brussels":                     "Europe/Brussels",
+	"Copenhagen":                   "Europe/Copenhagen",
+	"Madrid":                       "Europe/Madrid",
+	"Paris":                        "Europe/Paris",
+	"Amsterdam":                    "Europe/Amsterdam",
+	"Berlin":                       "Europe/Berlin",
+	"Bern":                         "Europe/Zurich",
+	"Zurich":                       "Europe/Zurich",
+	"Rome":                         "Europe/Rome",
+	"Stockholm":                    "Europe/Stockholm",
+	"Vienna":                       "Europe/Vienna",
+	"West Central Africa":          "Africa/Algiers",
+	"Bucharest":                    "Europe/Bucharest",
+	"Cairo":                        "Africa/Cairo",
+	"Helsinki":                     "Europe/Helsinki",
+	"Kyiv":                         "Europe/Kiev",
+	"Riga":                         "Europe/Riga",
+	"Sofia":                        "Europe/Sofia",
+	"Tallinn":                      "Europe/Tallinn",
+	"Vilnius":                      "Europe/Vilnius",
+	"Athens":                       "Europe/Athens",
+	"Istanbul":                     "Europe/Istanbul",
+	"Minsk":                        "Europe/Minsk",
+	"Jerusalem":                    "Asia/Jerusalem",
+	"Harare":                       "Africa/Harare",
+	"Pretoria":                     "Africa/Johannesburg",
+	"Kaliningrad":                  "Europe/Kaliningrad",
+	"Moscow":                       "Europe/Moscow",
+	"St. Petersburg":               "Europe/Moscow",
+	"Volgograd":                    "Europe/Volgograd",
+	"Samara":                       "Europe/Samara",
+	"Kuwait":                       "Asia/Kuwait",
+	"Riyadh":                       "Asia/Riyadh",
+	"Nairobi":                      "Africa/Nairobi",
+	"Baghdad":                      "Asia/Baghdad",
+	"Tehran":                       "Asia/Tehran",
+	"Abu Dhabi":                    "Asia/Muscat",
+	"Muscat":                       "Asia/Muscat",
+	"Baku":                         "Asia/Baku",
+	"Tbilisi":                      "Asia/Tbilisi",
+	"Yerevan":                      "Asia/Yerevan",
+	"Kabul":                        "Asia/Kabul",
+	"Ekaterinburg":                 "Asia/Yekaterinburg",
+	"Islamabad":                    "Asia/Karachi",
+	"Karachi":                      "Asia/Karachi",
+	"Tashkent":                     "Asia/Tashkent",
+	"Chennai":                      "Asia/Kolkata",
+	"Kolkata":                      "Asia/Kolkata",
+	"Mumbai":                       "Asia/Kolkata",
+	"New Delhi":                    "Asia/Kolkata",
+	"Kathmandu":                    "Asia/Kathmandu",
+	"Astana":                       "Asia/Dhaka",
+	"Dhaka":                        "Asia/Dhaka",
+	"Sri Jayawardenepura":          "Asia/Colombo",
+	"Almaty":                       "Asia/Almaty",
+	"Novosibirsk":                  "Asia/Novosibirsk",
+	"Rangoon":                      "Asia/Rangoon",
+	"Bangkok":                      "Asia/Bangkok",
+	"Hanoi":                        "Asia/Bangkok",
+	"Jakarta":                      "Asia/Jakarta",
+	"Krasnoyarsk":                  "Asia/Krasnoyarsk",
+	"Beijing":                      "Asia/Shanghai",
+	"Chongqing":                    "Asia/Chongqing",
+	"Hong Kong":                    "Asia/Hong_Kong",
+	"Urumqi":                       "Asia/Urumqi",
+	"Kuala Lumpur":                 "Asia/Kuala_Lumpur",
+	"Singapore":                    "Asia/Singapore",
+	"Taipei":                       "Asia/Taipei",
+	"Perth":                        "Australia/Perth",
+	"Irkutsk":                      "Asia/Irkutsk",
+	"Ulaanbaatar":                  "Asia/Ulaanbaatar",
+	"Seoul":                        "Asia/Seoul",
+	"Osaka":                        "Asia/Tokyo",
+	"Sapporo":                      "Asia/Tokyo",
+	"Tokyo":                        "Asia/Tokyo",
+	"Yakutsk":                      "Asia/Yakutsk",
+	"Darwin":                       "Australia/Darwin",
+	"Adelaide":                     "Australia/Adelaide",
+	"Canberra":                     "Australia/Melbourne",
+	"Melbourne":                    "Australia/Melbourne",
+	"Sydney":                       "Australia/Sydney",
+	"Brisbane":                     "Australia/Brisbane",
+	"Hobart":                       "Australia/Hobart",
+	"Vladivostok":                  "Asia/Vladivostok",
+	"Guam":                         "Pacific/Guam",
+	"Port Moresby":                 "Pacific/Port_Moresby",
+	"Magadan":                      "Asia/Magadan",
+	"Srednekolymsk":                "Asia/Srednekolymsk",
+	"Solomon Is.":                  "Pacific/Guadalcanal",
+	"New Caledonia":                "Pacific/Noumea",
+	"Fiji":                         "Pacific/Fiji",
+	"Kamchatka":                    "Asia/Kamchatka",
+	"Marshall Is.":                 "Pacific/Majuro",
+	"Auckland":                     "Pacific/Auckland",
+	"Wellington":                   "Pacific/Auckland",
+	"Nuku'alofa":                   "Pacific/Tongatapu",
+	"Tokelau Is.":                  "Pacific/Fakaofo",
+	"Chatham Is.":                  "Pacific/Chatham",
+	"Samoa":                        "Pacific/Apia",
+}
+
+// parseCron parses a cron expression in the dialect of GitLab.
+func parseCron(expression string) (*cronSchedule, error) {
+	expression = strings.TrimSpace(expression)
+	if shortcut, ok := cronShortcuts[strings.ToLower(expression)]; ok {
+		expression = shortcut
+	}
+
+	fields := strings.Fields(expression)
+	// The seconds are validated, but ignored, because GitLab doesn't run schedules more precisely than every minute.
+	if len(fields) == len(cronFields)+1 {
+		if _, err := parseCronField(fields[0], cronField{name: "second", min: 0, max: 59}); err != nil {
+			return nil, fmt.Errorf("invalid second %q in %q: %v", fields[0], expression, err)
+		}
+		fields = fields[1:]
+	}
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("expected %d fields (minute, hour, day of month, month and day of week), optionally preceded by the second, got %d in %q", len(cronFields), len(fields), expression)
+	}
+
+	schedule := &cronSchedule{}
+	bits := make([]uint64, len(fields))
+	for i, field := range fields {
+		var err error
+		rest := field
+		switch i {
+		case 2:
+			rest = schedule.parseLastDayOfMonth(field)
+		case 4:
+			rest, err = schedule.parseNthDaysOfWeek(field)
+		}
+		if err == nil && rest != "" {
+			bits[i], err = parseCronField(rest, cronFields[i])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q in %q: %v", cronFields[i].name, field, expression, err)
+		}
+	}
+
+	// Sunday can be written as 0 or 7.
+	if bits[4]&(1<<7) != 0 {
+		bits[4] = bits[4]&^(1<<7) | 1
+	}
+
+	schedule.minute = bits[0]
+	schedule.hour = bits[1]
+	schedule.dayOfMonth = bits[2]
+	schedule.month = bits[3]
+	schedule.dayOfWeek = bits[4]
+	schedule.dayOfMonthStar = strings.HasPrefix(fields[2], "*")
+	schedule.dayOfWeekStar = strings.HasPrefix(fields[4], "*")
+
+	return schedule, nil
+}
+
+// parseLastDayOfMonth records L in the list of the day of month field and returns the other parts of the list.
+func (s *cronSchedule) parseLastDayOfMonth(field string) string {
+	var rest []string
+
+	for _, part := range strings.Split(field, ",") {
+		if strings.EqualFold(part, "L") {
+			s.lastDayOfMonth = true
+			continue
+		}
+		rest = append(rest, part)
+	}
+
+	return strings.Join(rest, ",")
+}
+
+// parseNthDaysOfWeek records the weekdays with an occurrence, e.g. mon#2 or fri#L, in the list of the day of week field
+// and returns the other parts of the list.
+func (s *cronSchedule) parseNthDaysOfWeek(field string) (string, error) {
+	var rest []string
+
+	for _, part := range strings.Split(field, ",") {
+		i := strings.Index(part, "#")
+		if i < 0 {
+			rest = append(rest, part)
+			continue
+		}
+
+		weekday, err := parseCronValue(part[:i], cronFields[4])
+		if err != nil {
+			return "", err
+		}
+
+		n := -1
+		if occurrence := part[i+1:]; !strings.EqualFold(occurrence, "L") && occurrence != "-1" {
+			if n, err = strconv.Atoi(occurrence); err != nil || n < 1 || n > 5 {
+				return "", fmt.Errorf("invalid occurrence %q, expected 1 to 5 or L", occurrence)
+			}
+		}
+
+		s.nthDaysOfWeek = append(s.nthDaysOfWeek, cronNthDayOfWeek{weekday: time.Weekday(weekday % 7), n: n})
+	}
+
+	return strings.Join(rest, ","), nil
+}
+
+func parseCronField(field string, spec cronField) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			rangePart = part[:i]
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step %q", part[i+1:])
+			}
+		}
+
+		start, end := spec.min, spec.max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if start, err = parseCronValue(bounds[0], spec); err != nil {
+				return 0, err
+			}
+			if end, err = parseCronValue(bounds[1], spec); err != nil {
+				return 0, err
+			}
+			if start > end {
+				return 0, fmt.Errorf("range %q is reversed", rangePart)
+			}
+		default:
+			value, err := parseCronValue(rangePart, spec)
+			if err != nil {
+				return 0, err
+			}
+			start = value
+			// A single value with a step, e.g. 5/15, runs from the value to the maximum.
+			if step == 1 {
+				end = value
+			}
+		}
+
+		for value := start; value <= end; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+
+	return bits, nil
+}
+
+func parseCronValue(value string, spec cronField) (int, error) {
+	if n, ok := spec.names[strings.ToLower(value)]; ok {
+		return n, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", value)
+	}
+	if n < spec.min || n > spec.max {
+		return 0, fmt.Errorf("%d is not between %d and %d", n, spec.min, spec.max)
+	}
+
+	return n, nil
+}
+
+// next returns the first time after t that matches the schedule, in the location of t.
+// The zero time is returned if there is no such time within the next five years, e.g. for February 30th.
+func (s *cronSchedule) next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+// prev returns the last time at or before t that matches the schedule, in the location of t.
+// The zero time is returned if there is no such time within the last five years.
+func (s *cronSchedule) prev(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute)
+	limit := t.AddDate(-5, 0, 0)
+
+	for t.After(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc).Add(-time.Minute)
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc).Add(-time.Minute)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc).Add(-time.Minute)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(-time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (s *cronSchedule) matchesDay(t time.Time) bool {
+	dayOfMonth := s.dayOfMonth&(1<<uint(t.Day())) != 0 || s.lastDayOfMonth && t.AddDate(0, 0, 1).Day() == 1
+	dayOfWeek := s.dayOfWeek&(1<<uint(t.Weekday())) != 0 || s.matchesNthDayOfWeek(t)
+
+	if s.dayOfMonthStar || s.dayOfWeekStar {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
+
+func (s *cronSchedule) matchesNthDayOfWeek(t time.Time) bool {
+	for _, nth := range s.nthDaysOfWeek {
+		if nth.weekday != t.Weekday() {
+			continue
+		}
+		if nth.n == -1 {
+			if t.AddDate(0, 0, 7).Month() != t.Month() {
+				return true
+			}
+		} else if nth.n == (t.Day()-1)/7+1 {
+			return true
+		}
+	}
+	return false
+}
+
+// loadCronTimezone returns the location of a time zone of the tz database or a Rails time zone name.
+func loadCronTimezone(name string) (*time.Location, error) {
+	if tz, ok := activeSupportTimeZones[name]; ok {
+		name = tz
+	}
+
+	if name == "" || name == "Local" {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+
+	return time.LoadLocation(name)
+}
+
+// nextCronRun returns the next time after t the cron expression matches in the time zone.
+func nextCronRun(expression string, timezone string, t time.Time) (time.Time, error) {
+	schedule, err := parseCron(expression)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	loc, err := loadCronTimezone(timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	next := schedule.next(t.In(loc))
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("cron expression %q never matches", expression)
+	}
+
+	return next, nil
+}
+
+// currentOrNextCronWindow returns the window between the start and the end cron expression in the time zone,
+// which is active at t, or else the next one after t.
+// Like GitLab does for freeze periods, a window is active if the start matched more recently than the end.
+func currentOrNextCronWindow(startExpression, endExpression string, timezone string, t time.Time) (time.Time, time.Time, error) {
+	start, err := parseCron(startExpression)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	end, err := parseCron(endExpression)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	loc, err := loadCronTimezone(timezone)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	t = t.In(loc)
+	windowStart := start.prev(t)
+	if windowStart.IsZero() || !windowStart.After(end.prev(t)) {
+		if windowStart, err = nextCronRun(startExpression, timezone, t); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+
+	windowEnd, err := nextCronRun(endExpression, timezone, windowStart)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	return windowStart, windowEnd, nil
+}
+
+func validateCronExpression(v interface{}, p cty.Path) diag.Diagnostics {
+	if _, err := parseCron(v.(string)); err != nil {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Invalid cron expression",
+			Detail:        err.Error(),
+			AttributePath: p,
+		}}
+	}
+	return nil
+}
+
+func validateCronTimezone(v interface{}, p cty.Path) diag.Diagnostics {
+	if _, err := loadCronTimezone(v.(string)); err != nil {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Invalid time zone",
+			Detail:        fmt.Sprintf("%q is neither in the tz database, e.g. `Europe/Berlin`, nor a time zone name of Rails, e.g. `Berlin`", v.(string)),
+			AttributePath: p,
+		}}
+	}
+	return nil
+}
+
+// customizeDiffCronChange marks the attributes computed from cron expressions as unknown when any of the keys change,
+// so that the plan doesn't show the stale next run.
+func customizeDiffCronChange(keys []string, computed ...string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		if d.Id() == "" {
+			return nil
+		}
+
+		changed := false
+		for _, key := range keys {
+			changed = changed || d.HasChange(key)
+		}
+		if !changed {
+			return nil
+		}
+
+		for _, key := range computed {
+			if err := d.SetNewComputed(key); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,155 @@
+package gitlab
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+)
+
+func TestGitlab_validateCronExpression(t *testing.T) {
+	cases := []struct {
+		Value    string
+		ErrCount int
+	}{
+		{Value: "0 1 * * *", ErrCount: 0},
+		{Value: "*/15 9-17 * * mon-fri", ErrCount: 0},
+		{Value: "0 0 1,15 JAN-jun 0,7", ErrCount: 0},
+		{Value: "5/10 * * * *", ErrCount: 0},
+		{Value: "@weekly", ErrCount: 0},
+		{Value: "0 0 1 * * *", ErrCount: 0},
+		{Value: "0 0 L * *", ErrCount: 0},
+		{Value: "0 0 1,15,L * *", ErrCount: 0},
+		{Value: "0 0 * * mon#2", ErrCount: 0},
+		{Value: "0 0 * * fri#L,sun", ErrCount: 0},
+		{Value: "", ErrCount: 1},
+		{Value: "0 1 * *", ErrCount: 1},
+		{Value: "60 0 1 * * *", ErrCount: 1},
+		{Value: "0 0 0 1 * * *", ErrCount: 1},
+		{Value: "0 0 L-1 * *", ErrCount: 1},
+		{Value: "0 0 * * mon#6", ErrCount: 1},
+		{Value: "0 0 * * #2", ErrCount: 1},
+		{Value: "60 * * * *", ErrCount: 1},
+		{Value: "0 24 * * *", ErrCount: 1},
+		{Value: "0 0 0 * *", ErrCount: 1},
+		{Value: "0 0 * 13 *", ErrCount: 1},
+		{Value: "0 0 * * 8", ErrCount: 1},
+		{Value: "0 0 * * fry", ErrCount: 1},
+		{Value: "0 17-9 * * *", ErrCount: 1},
+		{Value: "*/0 * * * *", ErrCount: 1},
+		{Value: "@every 5m", ErrCount: 1},
+	}
+
+	for _, tc := range cases {
+		diags := validateCronExpression(tc.Value, cty.Path{cty.IndexStep{Key: cty.StringVal("cron")}})
+		if len(diags) != tc.ErrCount {
+			t.Errorf("expected %d validation errors for %q, got %d: %v", tc.ErrCount, tc.Value, len(diags), diags)
+		}
+	}
+}
+
+func TestGitlab_validateCronTimezone(t *testing.T) {
+	cases := []struct {
+		Value    string
+		ErrCount int
+	}{
+		{Value: "UTC", ErrCount: 0},
+		{Value: "Europe/Berlin", ErrCount: 0},
+		{Value: "America/Argentina/Buenos_Aires", ErrCount: 0},
+		{Value: "Pacific Time (US & Canada)", ErrCount: 0},
+		{Value: "Berlin", ErrCount: 0},
+		{Value: "", ErrCount: 1},
+		{Value: "Local", ErrCount: 1},
+		{Value: "Europe/Atlantis", ErrCount: 1},
+		{Value: "berlin", ErrCount: 1},
+	}
+
+	for _, tc := range cases {
+		diags := validateCronTimezone(tc.Value, cty.Path{cty.IndexStep{Key: cty.StringVal("cron_timezone")}})
+		if len(diags) != tc.ErrCount {
+			t.Errorf("expected %d validation errors for %q, got %d: %v", tc.ErrCount, tc.Value, len(diags), diags)
+		}
+	}
+}
+
+func TestGitlab_nextCronRun(t *testing.T) {
+	// A Wednesday
+	now := time.Date(2021, time.December, 15, 10, 30, 45, 0, time.UTC)
+
+	cases := []struct {
+		Cron     string
+		Timezone string
+		Expected string
+	}{
+		{Cron: "0 1 * * *", Timezone: "UTC", Expected: "2021-12-16T01:00:00Z"},
+		{Cron: "* * * * *", Timezone: "UTC", Expected: "2021-12-15T10:31:00Z"},
+		{Cron: "*/15 * * * *", Timezone: "UTC", Expected: "2021-12-15T10:45:00Z"},
+		{Cron: "0 9-17 * * mon-fri", Timezone: "UTC", Expected: "2021-12-15T11:00:00Z"},
+		{Cron: "0 0 * * sat", Timezone: "UTC", Expected: "2021-12-18T00:00:00Z"},
+		{Cron: "0 0 * * 7", Timezone: "UTC", Expected: "2021-12-19T00:00:00Z"},
+		{Cron: "@monthly", Timezone: "UTC", Expected: "2022-01-01T00:00:00Z"},
+		{Cron: "@yearly", Timezone: "UTC", Expected: "2022-01-01T00:00:00Z"},
+		{Cron: "0 0 29 feb *", Timezone: "UTC", Expected: "2024-02-29T00:00:00Z"},
+		// Both day fields are restricted, so either of them matches.
+		{Cron: "0 0 1 * fri", Timezone: "UTC", Expected: "2021-12-17T00:00:00Z"},
+		// The seconds are ignored.
+		{Cron: "30 0 1 * * *", Timezone: "UTC", Expected: "2021-12-16T01:00:00Z"},
+		{Cron: "0 0 L * *", Timezone: "UTC", Expected: "2021-12-31T00:00:00Z"},
+		{Cron: "0 0 L feb *", Timezone: "UTC", Expected: "2022-02-28T00:00:00Z"},
+		{Cron: "0 0 * * mon#2", Timezone: "UTC", Expected: "2022-01-10T00:00:00Z"},
+		{Cron: "0 0 * * wed#3", Timezone: "UTC", Expected: "2022-01-19T00:00:00Z"},
+		{Cron: "0 0 * * fri#L", Timezone: "UTC", Expected: "2021-12-31T00:00:00Z"},
+		{Cron: "0 1 * * *", Timezone: "Europe/Berlin", Expected: "2021-12-16T01:00:00+01:00"},
+		{Cron: "0 1 * * *", Timezone: "Tokyo", Expected: "2021-12-16T01:00:00+09:00"},
+	}
+
+	for _, tc := range cases {
+		next, err := nextCronRun(tc.Cron, tc.Timezone, now)
+		if err != nil {
+			t.Errorf("unexpected error for %q in %s: %v", tc.Cron, tc.Timezone, err)
+			continue
+		}
+		if got := next.Format(time.RFC3339); got != tc.Expected {
+			t.Errorf("expected next run of %q in %s at %s, got %s", tc.Cron, tc.Timezone, tc.Expected, got)
+		}
+	}
+
+	if _, err := nextCronRun("0 0 30 feb *", "UTC", now); err == nil {
+		t.Errorf("expected an error for a cron expression that never matches")
+	}
+}
+
+func TestGitlab_currentOrNextCronWindow(t *testing.T) {
+	// A Wednesday
+	now := time.Date(2021, time.December, 15, 10, 30, 45, 0, time.UTC)
+
+	cases := []struct {
+		Start, End    string
+		Timezone      string
+		ExpectedStart string
+		ExpectedEnd   string
+	}{
+		// Active windows
+		{Start: "0 9 * * *", End: "0 17 * * *", Timezone: "UTC", ExpectedStart: "2021-12-15T09:00:00Z", ExpectedEnd: "2021-12-15T17:00:00Z"},
+		{Start: "0 0 * * mon", End: "0 0 * * sat", Timezone: "UTC", ExpectedStart: "2021-12-13T00:00:00Z", ExpectedEnd: "2021-12-18T00:00:00Z"},
+		{Start: "0 0 L nov *", End: "0 0 L dec *", Timezone: "UTC", ExpectedStart: "2021-11-30T00:00:00Z", ExpectedEnd: "2021-12-31T00:00:00Z"},
+		// Next windows
+		{Start: "0 18 * * *", End: "0 8 * * *", Timezone: "UTC", ExpectedStart: "2021-12-15T18:00:00Z", ExpectedEnd: "2021-12-16T08:00:00Z"},
+		{Start: "0 23 * * fri", End: "0 7 * * mon", Timezone: "UTC", ExpectedStart: "2021-12-17T23:00:00Z", ExpectedEnd: "2021-12-20T07:00:00Z"},
+		{Start: "0 9 * * *", End: "0 17 * * *", Timezone: "Tokyo", ExpectedStart: "2021-12-16T09:00:00+09:00", ExpectedEnd: "2021-12-16T17:00:00+09:00"},
+	}
+
+	for _, tc := range cases {
+		start, end, err := currentOrNextCronWindow(tc.Start, tc.End, tc.Timezone, now)
+		if err != nil {
+			t.Errorf("unexpected error for %q to %q in %s: %v", tc.Start, tc.End, tc.Timezone, err)
+			continue
+		}
+		if got := start.Format(time.RFC3339); got != tc.ExpectedStart {
+			t.Errorf("expected window of %q to %q in %s to start at %s, got %s", tc.Start, tc.End, tc.Timezone, tc.ExpectedStart, got)
+		}
+		if got := end.Format(time.RFC3339); got != tc.ExpectedEnd {
+			t.Errorf("expected window of %q to %q in %s to end at %s, got %s", tc.Start, tc.End, tc.Timezone, tc.ExpectedEnd, got)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/mitchellh/hashstructure"
+	"github.com/xanzy/go-gitlab"
+)
+
+func dataSourceGitlabBranches() *schema.Resource {
+	branchSchema := map[string]*schema.Schema{
+		"name": {
+			Description: "The name of the branch.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+	}
+	for k, v := range resourceGitlabBranchComputedSchema {
+		branchSchema[k] = v
+	}
+
+	return &schema.Resource{
+		Description: "Provides details about the branches of a given project.",
+
+		ReadContext: dataSourceGitlabBranchesRead,
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Description:  "The integer or path with namespace that uniquely identifies the project.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"search": {
+				Description: "Only return branches containing this search term, as supported by the GitLab API. Use `^term` and `term$` to find branches that begin and end with `term` respectively.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"regex": {
+				Description:  "Only return branches with a name matching this regular expression.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"branches": {
+				Description: "The list of branches, as defined below.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: branchSchema,
+				},
+			},
+		},
+	}
+}
+
+func dataSourceGitlabBranchesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	project := d.Get("project").(string)
+
+	var nameRegex *regexp.Regexp
+	if v, ok := d.GetOk("regex"); ok {
+		nameRegex = regexp.MustCompile(v.(string))
+	}
+
+	options := &gitlab.ListBranchesOptions{
+		ListOptions: gitlab.ListOptions{
+			PerPage: 100,
+			Page:    1,
+		},
+	}
+	if v, ok := d.GetOk("search"); ok {
+		options.Search = gitlab.String(v.(string))
+	}
+
+	// The ID is hashed before paginating, which changes the options, and includes the client side filter.
+	h, err := hashstructure.Hash([]interface{}{*options, d.Get("regex").(string)}, nil)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] list gitlab branches for project %s", project)
+
+	branches := make([]map[string]interface{}, 0)
+	for options.Page != 0 {
+		page, resp, err := client.Branches.ListBranches(project, options, gitlab.WithContext(ctx))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		for _, branch := range page {
+			if nameRegex != nil && !nameRegex.MatchString(branch.Name) {
+				continue
+			}
+			branches = append(branches, flattenGitlabBranch(branch))
+		}
+
+		options.Page = resp.NextPage
+	}
+
+	if err := d.Set("branches", branches); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s-%d", project, h))
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package gitlab
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func TestAccDataGitlabBranches_regex(t *testing.T) {
+	testAccCheck(t)
+
+	client := testAccNewClient(t)
+	project := testAccCreateProject(t, client)
+
+	for _, name := range []string{"release/1.0", "release/1.1", "feature/meow"} {
+		if _, _, err := client.Branches.CreateBranch(project.ID, &gitlab.CreateBranchOptions{
+			Branch: gitlab.String(name),
+			Ref:    gitlab.String(project.DefaultBranch),
+		}); err != nil {
+			t.Fatalf("could not create test branch: %v", err)
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "gitlab_branches" "releases" {
+  project = %d
+  regex   = "^release/"
+}
+				`, project.ID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.gitlab_branches.releases", "branches.#", "2"),
+					resource.TestCheckResourceAttr("data.gitlab_branches.releases", "branches.0.name", "release/1.0"),
+					resource.TestCheckResourceAttr("data.gitlab_branches.releases", "branches.1.name", "release/1.1"),
+					resource.TestCheckResourceAttrSet("data.gitlab_branches.releases", "branches.0.commit_sha"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,267 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/mitchellh/hashstructure"
+	"github.com/xanzy/go-gitlab"
+)
+
+// gitlabCILintJob is a job of the merged CI configuration, which go-gitlab doesn't expose.
+type gitlabCILintJob struct {
+	Name         string   `json:"name"`
+	Stage        string   `json:"stage"`
+	BeforeScript []string `json:"before_script"`
+	Script       []string `json:"script"`
+	AfterScript  []string `json:"after_script"`
+	TagList      []string `json:"tag_list"`
+	Environment  string   `json:"environment"`
+	When         string   `json:"when"`
+	AllowFailure bool     `json:"allow_failure"`
+}
+
+// gitlabCILintResult combines the lint results of go-gitlab with the jobs.
+type gitlabCILintResult struct {
+	Status     string             `json:"status"`
+	Valid      bool               `json:"valid"`
+	Errors     []string           `json:"errors"`
+	Warnings   []string           `json:"warnings"`
+	MergedYaml string             `json:"merged_yaml"`
+	Jobs       []*gitlabCILintJob `json:"jobs"`
+}
+
+// gitlabCILintOptions are the options of the CI lint API, including the ones go-gitlab doesn't support.
+type gitlabCILintOptions struct {
+	Content     *string `url:"content,omitempty" json:"content,omitempty"`
+	Ref         *string `url:"ref,omitempty" json:"ref,omitempty"`
+	DryRun      *bool   `url:"dry_run,omitempty" json:"dry_run,omitempty"`
+	IncludeJobs *bool   `url:"include_jobs,omitempty" json:"include_jobs,omitempty"`
+}
+
+func dataSourceGitlabCILint() *schema.Resource {
+	return &schema.Resource{
+		Description: "Validates CI/CD configuration with the [CI lint API](https://docs.gitlab.com/ee/api/lint.html).\n\n" +
+			"Either the `content` of a configuration or a `project` and `ref` to validate the configuration of the project at that ref is required. " +
+			"Set `fail_on_invalid` and let `gitlab_repository_file` depend on this data source to fail before invalid configuration is committed.",
+
+		ReadContext: dataSourceGitlabCILintRead,
+		Schema: map[string]*schema.Schema{
+			"content": {
+				Description:  "The CI/CD configuration to validate.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"content", "ref"},
+			},
+			"project": {
+				Description: "The ID or full path of the project to validate the configuration in. Includes and variables of the project are resolved. Required when validating a `ref`.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"ref": {
+				Description:  "The branch or tag of the `project` whose configuration is validated.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"project"},
+			},
+			"dry_run": {
+				Description:  "Simulate the creation of a pipeline for the default branch of the `project` to find more errors, e.g. in rules.",
+				Type:         schema.TypeBool,
+				Optional:     true,
+				Default:      false,
+				RequiredWith: []string{"project"},
+			},
+			"fail_on_invalid": {
+				Description: "Return an error with the validation errors if the configuration is invalid.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"valid": {
+				Description: "Whether the configuration is valid.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"errors": {
+				Description: "The validation errors.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"warnings": {
+				Description: "The validation warnings.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"merged_yaml": {
+				Description: "The configuration with all includes merged.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"jobs": {
+				Description: "The jobs of the configuration.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Description: "The name of the job.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"stage": {
+							Description: "The stage of the job.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"before_script": {
+							Description: "The commands run before the script of the job.",
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"script": {
+							Description: "The commands of the job.",
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"after_script": {
+							Description: "The commands run after the script of the job.",
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"tags": {
+							Description: "The runner tags of the job.",
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"environment": {
+							Description: "The environment the job deploys to.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"when": {
+							Description: "When the job is run.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"allow_failure": {
+							Description: "Whether the job is allowed to fail.",
+							Type:        schema.TypeBool,
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceGitlabCILintRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+
+	options := &gitlabCILintOptions{
+		IncludeJobs: gitlab.Bool(true),
+	}
+	if v, ok := d.GetOk("content"); ok {
+		options.Content = gitlab.String(v.(string))
+	}
+	if v, ok := d.GetOk("ref"); ok {
+		options.Ref = gitlab.String(v.(string))
+	}
+	if d.Get("dry_run").(bool) {
+		options.DryRun = gitlab.Bool(true)
+	}
+
+	project := d.Get("project").(string)
+	result, err := lintGitlabCIConfig(ctx, client, project, options)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("valid", result.Valid)
+	if err := d.Set("errors", result.Errors); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("warnings", result.Warnings); err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("merged_yaml", result.MergedYaml)
+	if err := d.Set("jobs", flattenCILintJobs(result.Jobs)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	h, err := hashstructure.Hash([]interface{}{project, *options}, nil)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(fmt.Sprintf("%d", h))
+
+	if !result.Valid && d.Get("fail_on_invalid").(bool) {
+		return diag.Errorf("CI configuration is invalid: %s", strings.Join(result.Errors, "; "))
+	}
+
+	return nil
+}
+
+// lintGitlabCIConfig validates the CI configuration with the lint API of the project,
+// or with the instance wide lint API if no project is given.
+func lintGitlabCIConfig(ctx context.Context, client *gitlab.Client, project string, options *gitlabCILintOptions) (*gitlabCILintResult, error) {
+	method, u := http.MethodPost, "ci/lint"
+	switch {
+	case project == "":
+		log.Printf("[DEBUG] lint gitlab CI configuration")
+	case options.Content != nil:
+		log.Printf("[DEBUG] lint gitlab CI configuration in project %s", project)
+		u = fmt.Sprintf("projects/%s/ci/lint", gitlab.PathEscape(project))
+	default:
+		log.Printf("[DEBUG] lint gitlab CI configuration of project %s at %q", project, *options.Ref)
+		method, u = http.MethodGet, fmt.Sprintf("projects/%s/ci/lint", gitlab.PathEscape(project))
+	}
+
+	req, err := client.NewRequest(method, u, options, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(gitlabCILintResult)
+	if _, err := client.Do(req, result); err != nil {
+		return nil, err
+	}
+
+	// The instance wide lint API reports a status instead of the validity.
+	if result.Status != "" {
+		result.Valid = result.Status == "valid"
+	}
+
+	return result, nil
+}
+
+func flattenCILintJobs(jobs []*gitlabCILintJob) []map[string]interface{} {
+	values := make([]map[string]interface{}, 0, len(jobs))
+
+	for _, job := range jobs {
+		values = append(values, map[string]interface{}{
+			"name":          job.Name,
+			"stage":         job.Stage,
+			"before_script": job.BeforeScript,
+			"script":        job.Script,
+			"after_script":  job.AfterScript,
+			"tags":          job.TagList,
+			"environment":   job.Environment,
+			"when":          job.When,
+			"allow_failure": job.AllowFailure,
+		})
+	}
+
+	return values
+}
@@ -0,0 +1,68 @@
+package gitlab
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataGitlabCILint_basic(t *testing.T) {
+	testAccCheck(t)
+
+	client := testAccNewClient(t)
+	project := testAccCreateProject(t, client)
+
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			// Lint valid content
+			{
+				Config: fmt.Sprintf(`
+data "gitlab_ci_lint" "this" {
+  project = %d
+  content = <<EOT
+build:
+  stage: build
+  script:
+    - make
+EOT
+}
+`, project.ID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.gitlab_ci_lint.this", "valid", "true"),
+					resource.TestCheckResourceAttr("data.gitlab_ci_lint.this", "errors.#", "0"),
+					resource.TestCheckResourceAttr("data.gitlab_ci_lint.this", "jobs.#", "1"),
+					resource.TestCheckResourceAttr("data.gitlab_ci_lint.this", "jobs.0.name", "build"),
+					resource.TestCheckResourceAttr("data.gitlab_ci_lint.this", "jobs.0.script.0", "make"),
+					resource.TestCheckResourceAttrSet("data.gitlab_ci_lint.this", "merged_yaml"),
+				),
+			},
+			// Lint invalid content without failing
+			{
+				Config: fmt.Sprintf(`
+data "gitlab_ci_lint" "this" {
+  project = %d
+  content = "build: {}"
+}
+`, project.ID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.gitlab_ci_lint.this", "valid", "false"),
+					resource.TestCheckResourceAttrSet("data.gitlab_ci_lint.this", "errors.0"),
+				),
+			},
+			// Fail on invalid content
+			{
+				Config: fmt.Sprintf(`
+data "gitlab_ci_lint" "this" {
+  project         = %d
+  content         = "build: {}"
+  fail_on_invalid = true
+}
+`, project.ID),
+				ExpectError: regexp.MustCompile("CI configuration is invalid"),
+			},
+		},
+	})
+}
@@ -0,0 +1,161 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/mitchellh/hashstructure"
+	"github.com/xanzy/go-gitlab"
+)
+
+func dataSourceGitlabProjectEnvironments() *schema.Resource {
+	return &schema.Resource{
+		Description: "Provides details about the environments of a given project.",
+
+		ReadContext: dataSourceGitlabProjectEnvironmentsRead,
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Description:  "The ID or full path of the project.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"states": {
+				Description:  "Only return environments in this state. Valid values are `available`, `stopping` and `stopped`.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"available", "stopping", "stopped"}, false),
+			},
+			"search": {
+				Description: "Only return environments whose name contains this string.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"environment_scope": {
+				Description: "Only return environments matched by this environment scope, e.g. `review/*`. Uses the same wildcard semantics as the `environment_scope` of `gitlab_project_variable`.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"environments": {
+				Description: "The list of environments.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Description: "The ID of the environment.",
+							Type:        schema.TypeInt,
+							Computed:    true,
+						},
+						"name": {
+							Description: "The name of the environment.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"slug": {
+							Description: "The name of the environment in lowercase, shortened to 63 bytes.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"state": {
+							Description: "The state of the environment.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"tier": {
+							Description: "The deployment tier of the environment.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"external_url": {
+							Description: "Place to link to for this environment.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceGitlabProjectEnvironmentsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	project := d.Get("project").(string)
+
+	options := &gitlab.ListEnvironmentsOptions{
+		ListOptions: gitlab.ListOptions{PerPage: 100, Page: 1},
+	}
+	if v, ok := d.GetOk("states"); ok {
+		options.States = gitlab.String(v.(string))
+	}
+	if v, ok := d.GetOk("search"); ok {
+		options.Search = gitlab.String(v.(string))
+	}
+	environmentScope := d.Get("environment_scope").(string)
+
+	log.Printf("[DEBUG] read gitlab environments of project %s", project)
+
+	environments, err := listGitlabEnvironments(ctx, client, project, options)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	values := make([]map[string]interface{}, 0, len(environments))
+	for _, environment := range environments {
+		// Environment scopes use the same wildcards as protected branches.
+		if environmentScope != "" && !protectedBranchNameMatches(environmentScope, environment.Name) {
+			continue
+		}
+		values = append(values, map[string]interface{}{
+			"id":           environment.ID,
+			"name":         environment.Name,
+			"slug":         environment.Slug,
+			"state":        environment.State,
+			"tier":         environment.Tier,
+			"external_url": environment.ExternalURL,
+		})
+	}
+
+	if err := d.Set("environments", values); err != nil {
+		return diag.FromErr(err)
+	}
+
+	h, err := hashstructure.Hash([]interface{}{*options, environmentScope}, nil)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s-%d", project, h))
+
+	return nil
+}
+
+func listGitlabEnvironments(ctx context.Context, client *gitlab.Client, project string, options *gitlab.ListEnvironmentsOptions) ([]*gitlabEnvironment, error) {
+	u := fmt.Sprintf("projects/%s/environments", gitlab.PathEscape(project))
+
+	environments := make([]*gitlabEnvironment, 0)
+
+	for options.Page != 0 {
+		req, err := client.NewRequest(http.MethodGet, u, options, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+		if err != nil {
+			return nil, err
+		}
+
+		var page []*gitlabEnvironment
+		resp, err := client.Do(req, &page)
+		if err != nil {
+			return nil, err
+		}
+
+		environments = append(environments, page...)
+		options.Page = resp.NextPage
+	}
+
+	return environments, nil
+}
@@ -0,0 +1,49 @@
+package gitlab
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/xanzy/go-gitlab"
+)
+
+func TestAccDataGitlabProjectEnvironments_basic(t *testing.T) {
+	testAccCheck(t)
+
+	client := testAccNewClient(t)
+	project := testAccCreateProject(t, client)
+
+	for _, name := range []string{"production", "review/feature-a", "review/feature-b"} {
+		if _, _, err := client.Environments.CreateEnvironment(project.ID, &gitlab.CreateEnvironmentOptions{Name: gitlab.String(name)}); err != nil {
+			t.Fatalf("could not create environment %q: %v", name, err)
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "gitlab_project_environments" "all" {
+  project = %d
+}
+
+data "gitlab_project_environments" "review" {
+  project           = %d
+  states            = "available"
+  environment_scope = "review/*"
+}
+`, project.ID, project.ID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.gitlab_project_environments.all", "environments.#", "3"),
+					resource.TestCheckResourceAttr("data.gitlab_project_environments.review", "environments.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("data.gitlab_project_environments.review", "environments.*", map[string]string{
+						"name":  "review/feature-a",
+						"state": "available",
+					}),
+				),
+			},
+		},
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -13,7 +14,7 @@ import (
 
 func dataSourceGitlabProjectProtectedBranches() *schema.Resource {
 	return &schema.Resource{
-		Description: "Provides details about all protected branches in a given project.",
+		Description: "Provides details about all protected branches in a given project, and which of them protect each existing branch of the project.",
 
 		ReadContext: dataSourceGitlabProjectProtectedBranchesRead,
 		Schema: map[string]*schema.Schema{
@@ -53,6 +54,26 @@ func dataSourceGitlabProjectProtectedBranches() *schema.Resource {
 					},
 				},
 			},
+			"branches": {
+				Description: "A list of the existing branches which are protected, sorted by name, and the protected branches that apply to them, as defined below.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Description: "The name of the branch.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"protected_by": {
+							Description: "The names of the protected branches, including wildcards like `release/*`, that match the branch.",
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -105,6 +126,34 @@ func dataSourceGitlabProjectProtectedBranchesRead(ctx context.Context, d *schema
 		return diag.FromErr(err)
 	}
 
+	var branchNames []string
+	protectedBy := make(map[string][]string)
+	for _, pb := range allProtectedBranches {
+		matches, err := listGitlabProtectedBranchMatches(ctx, client, project, pb.Name)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		for _, name := range matches {
+			if _, ok := protectedBy[name]; !ok {
+				branchNames = append(branchNames, name)
+			}
+			protectedBy[name] = append(protectedBy[name], pb.Name)
+		}
+	}
+	sort.Strings(branchNames)
+
+	branches := make([]map[string]interface{}, 0, len(branchNames))
+	for _, name := range branchNames {
+		branches = append(branches, map[string]interface{}{
+			"name":         name,
+			"protected_by": protectedBy[name],
+		})
+	}
+
+	if err := d.Set("branches", branches); err != nil {
+		return diag.FromErr(err)
+	}
+
 	h, err := hashstructure.Hash(*opts, nil)
 	if err != nil {
 		return diag.FromErr(err)
@@ -54,3 +54,62 @@ data "gitlab_project_protected_branches" "test" {
 }
 `, projectName, projectName)
 }
+
+func TestAccDataGitlabProjectProtectedBranches_wildcard(t *testing.T) {
+	projectName := fmt.Sprintf("tf-%s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataGitlabProjectProtectedBranchesConfigWildcard(projectName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.gitlab_project_protected_branches.test", "branches.#", "2"),
+					resource.TestCheckResourceAttr("data.gitlab_project_protected_branches.test", "branches.0.name", "main"),
+					resource.TestCheckResourceAttr("data.gitlab_project_protected_branches.test", "branches.0.protected_by.#", "1"),
+					resource.TestCheckResourceAttr("data.gitlab_project_protected_branches.test", "branches.0.protected_by.0", "main"),
+					resource.TestCheckResourceAttr("data.gitlab_project_protected_branches.test", "branches.1.name", "release/1.0"),
+					resource.TestCheckResourceAttr("data.gitlab_project_protected_branches.test", "branches.1.protected_by.#", "1"),
+					resource.TestCheckResourceAttr("data.gitlab_project_protected_branches.test", "branches.1.protected_by.0", "release/*"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataGitlabProjectProtectedBranchesConfigWildcard(projectName string) string {
+	return fmt.Sprintf(`
+resource "gitlab_project" "test" {
+  name                   = "%[1]s"
+  path                   = "%[1]s"
+  default_branch         = "main"
+  initialize_with_readme = true
+}
+
+resource "gitlab_branch" "release" {
+  project = gitlab_project.test.id
+  name    = "release/1.0"
+  ref     = "main"
+}
+
+resource "gitlab_branch" "topic" {
+  project = gitlab_project.test.id
+  name    = "topic"
+  ref     = "main"
+}
+
+resource "gitlab_branch_protection" "release" {
+  project            = gitlab_project.test.id
+  branch             = "release/*"
+  push_access_level  = "maintainer"
+  merge_access_level = "developer"
+}
+
+data "gitlab_project_protected_branches" "test" {
+  project_id = gitlab_branch_protection.release.project
+
+  depends_on = [gitlab_branch.release, gitlab_branch.topic]
+}
+`, projectName)
+}
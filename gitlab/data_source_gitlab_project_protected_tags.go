@@ -0,0 +1,119 @@
+package gitlab
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/xanzy/go-gitlab"
+)
+
+func dataSourceGitlabProjectProtectedTags() *schema.Resource {
+	return &schema.Resource{
+		Description: "Provides details about all protected tags in a given project.",
+
+		ReadContext: dataSourceGitlabProjectProtectedTagsRead,
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Description:  "The integer or path with namespace that uniquely identifies the project.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"protected_tags": {
+				Description: "A list of protected tags, as defined below.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Description: "The name of the protected tag or wildcard.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"create_access_levels": {
+							Description: "Describes which access levels, users, groups or deploy keys are allowed to create matching tags.",
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"access_level": {
+										Description: "The access level allowed to create matching tags.",
+										Type:        schema.TypeString,
+										Computed:    true,
+									},
+									"access_level_description": {
+										Description: "A description of the allowed access level, or the name of the user, group or deploy key if `user_id`, `group_id` or `deploy_key_id` are present.",
+										Type:        schema.TypeString,
+										Computed:    true,
+									},
+									"user_id": {
+										Description: "If present, indicates that the user is allowed to create matching tags.",
+										Type:        schema.TypeInt,
+										Computed:    true,
+									},
+									"group_id": {
+										Description: "If present, indicates that the group is allowed to create matching tags.",
+										Type:        schema.TypeInt,
+										Computed:    true,
+									},
+									"deploy_key_id": {
+										Description: "If present, indicates that the deploy key is allowed to create matching tags.",
+										Type:        schema.TypeInt,
+										Computed:    true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceGitlabProjectProtectedTagsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	project := d.Get("project_id").(string)
+
+	log.Printf("[DEBUG] read gitlab protected tags of project %s", project)
+
+	pts, err := listGitlabProtectedTags(ctx, client, project)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("protected_tags", flattenProtectedTags(pts)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(project)
+
+	return nil
+}
+
+func flattenProtectedTags(pts []*gitlabProtectedTag) []map[string]interface{} {
+	values := make([]map[string]interface{}, 0, len(pts))
+
+	for _, pt := range pts {
+		createAccessLevels := make([]map[string]interface{}, 0, len(pt.CreateAccessLevels))
+		for _, description := range pt.CreateAccessLevels {
+			createAccessLevels = append(createAccessLevels, map[string]interface{}{
+				"access_level":             accessLevel[description.AccessLevel],
+				"access_level_description": description.AccessLevelDescription,
+				"user_id":                  description.UserID,
+				"group_id":                 description.GroupID,
+				"deploy_key_id":            description.DeployKeyID,
+			})
+		}
+
+		values = append(values, map[string]interface{}{
+			"name":                 pt.Name,
+			"create_access_levels": createAccessLevels,
+		})
+	}
+
+	return values
+}
@@ -0,0 +1,47 @@
+package gitlab
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataGitlabProjectProtectedTags_basic(t *testing.T) {
+	projectName := fmt.Sprintf("tf-%s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataGitlabProjectProtectedTagsConfig(projectName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.gitlab_project_protected_tags.test", "protected_tags.#", "1"),
+					resource.TestCheckResourceAttr("data.gitlab_project_protected_tags.test", "protected_tags.0.name", "v*"),
+					resource.TestCheckResourceAttr("data.gitlab_project_protected_tags.test", "protected_tags.0.create_access_levels.0.access_level", "maintainer"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataGitlabProjectProtectedTagsConfig(projectName string) string {
+	return fmt.Sprintf(`
+resource "gitlab_project" "test" {
+  name = "%[1]s"
+  path = "%[1]s"
+}
+
+resource "gitlab_tag_protection" "test" {
+  project             = gitlab_project.test.id
+  tag                 = "v*"
+  create_access_level = "maintainer"
+}
+
+data "gitlab_project_protected_tags" "test" {
+  project_id = gitlab_tag_protection.test.project
+}
+`, projectName)
+}
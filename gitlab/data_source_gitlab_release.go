@@ -0,0 +1,165 @@
+package gitlab
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/xanzy/go-gitlab"
+)
+
+func dataSourceGitlabRelease() *schema.Resource {
+	return &schema.Resource{
+		Description: "Provides details about a release of a given project. If no `tag_name` is given, the latest release is returned.",
+
+		ReadContext: dataSourceGitlabReleaseRead,
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Description:  "The integer or path with namespace that uniquely identifies the project.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"tag_name": {
+				Description: "The tag of the release. Defaults to the tag of the latest release.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"name": {
+				Description: "The name of the release.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"description": {
+				Description: "The description of the release.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"milestones": {
+				Description: "The titles of the milestones the release is associated with.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"released_at": {
+				Description: "The date when the release is or will be ready.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"created_at": {
+				Description: "The date when the release was created.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"commit_sha": {
+				Description: "The SHA of the commit the release tag points to.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"assets": {
+				Description: "The assets of the release.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"links": {
+							Description: "The asset links of the release.",
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"id": {
+										Description: "The ID of the link.",
+										Type:        schema.TypeInt,
+										Computed:    true,
+									},
+									"name": {
+										Description: "The name of the link.",
+										Type:        schema.TypeString,
+										Computed:    true,
+									},
+									"url": {
+										Description: "The URL of the link.",
+										Type:        schema.TypeString,
+										Computed:    true,
+									},
+									"filepath": {
+										Description: "The path for a direct asset link.",
+										Type:        schema.TypeString,
+										Computed:    true,
+									},
+									"link_type": {
+										Description: "The type of the link.",
+										Type:        schema.TypeString,
+										Computed:    true,
+									},
+									"direct_asset_url": {
+										Description: "The full URL of the direct asset link.",
+										Type:        schema.TypeString,
+										Computed:    true,
+									},
+									"external": {
+										Description: "Whether the link points to an external resource.",
+										Type:        schema.TypeBool,
+										Computed:    true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceGitlabReleaseRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	project := d.Get("project").(string)
+	tagName := d.Get("tag_name").(string)
+
+	if tagName == "" {
+		log.Printf("[DEBUG] read latest gitlab release for project %s", project)
+
+		// Releases are sorted by their release date, with the latest first
+		releases, _, err := client.Releases.ListReleases(project, &gitlab.ListReleasesOptions{PerPage: 1}, gitlab.WithContext(ctx))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if len(releases) == 0 {
+			return diag.Errorf("project %s has no releases", project)
+		}
+		tagName = releases[0].TagName
+	}
+
+	log.Printf("[DEBUG] read gitlab release %q for project %s", tagName, project)
+
+	release, err := getGitlabRelease(ctx, client, project, tagName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(buildTwoPartID(&project, &release.TagName))
+	d.Set("tag_name", release.TagName)
+	d.Set("name", release.Name)
+	d.Set("description", release.Description)
+	d.Set("commit_sha", release.Commit.ID)
+	if release.ReleasedAt != nil {
+		d.Set("released_at", release.ReleasedAt.Format(time.RFC3339))
+	}
+	if release.CreatedAt != nil {
+		d.Set("created_at", release.CreatedAt.Format(time.RFC3339))
+	}
+	if err := d.Set("milestones", flattenReleaseMilestones(release.Milestones)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("assets", flattenReleaseAssets(release, nil)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package gitlab
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceGitlabRelease_basic(t *testing.T) {
+	testAccCheck(t)
+
+	client := testAccNewClient(t)
+	project := testAccCreateProject(t, client)
+
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "gitlab_release" "old" {
+  project     = %[1]d
+  tag_name    = "v1.0.0"
+  ref         = %[2]q
+  released_at = "2021-01-01T10:00:00Z"
+}
+
+resource "gitlab_release" "new" {
+  project     = %[1]d
+  tag_name    = "v1.1.0"
+  ref         = %[2]q
+  description = "The latest release"
+  released_at = "2021-06-01T10:00:00Z"
+
+  assets {
+    links {
+      name = "docs"
+      url  = "https://example.com/docs"
+    }
+  }
+}
+
+data "gitlab_release" "latest" {
+  project = %[1]d
+
+  depends_on = [gitlab_release.old, gitlab_release.new]
+}
+
+data "gitlab_release" "by_tag" {
+  project  = %[1]d
+  tag_name = gitlab_release.old.tag_name
+}
+				`, project.ID, project.DefaultBranch),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.gitlab_release.latest", "tag_name", "v1.1.0"),
+					resource.TestCheckResourceAttr("data.gitlab_release.latest", "description", "The latest release"),
+					resource.TestCheckResourceAttr("data.gitlab_release.latest", "assets.0.links.#", "1"),
+					resource.TestCheckResourceAttr("data.gitlab_release.by_tag", "tag_name", "v1.0.0"),
+					resource.TestCheckResourceAttrPair("data.gitlab_release.by_tag", "commit_sha", "gitlab_release.old", "commit_sha"),
+				),
+			},
+		},
+	})
+}
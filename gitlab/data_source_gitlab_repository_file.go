@@ -0,0 +1,115 @@
+package gitlab
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/xanzy/go-gitlab"
+)
+
+func dataSourceGitlabRepositoryFile() *schema.Resource {
+	return &schema.Resource{
+		Description: "Provides details about a file in a given project repository at a given ref.",
+
+		ReadContext: dataSourceGitlabRepositoryFileRead,
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Description:  "The integer or path with namespace that uniquely identifies the project.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"file_path": {
+				Description:  "The full path of the file. It must be relative to the root of the project without a leading slash `/`.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"ref": {
+				Description:  "The name of branch, tag or commit to read the file from.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"encoding": {
+				Description:  "The encoding of the returned `content`. Valid values are `base64` and `text`.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "base64",
+				ValidateFunc: validation.StringInSlice([]string{"base64", "text"}, false),
+			},
+			"file_name": {
+				Description: "The name of the file.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"size": {
+				Description: "The size of the file in bytes.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"content": {
+				Description: "The file content, in the requested `encoding`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"content_sha256": {
+				Description: "The SHA256 hash of the (decoded) file content.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"blob_id": {
+				Description: "The blob id of the file.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"commit_id": {
+				Description: "The commit id the `ref` points to.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"last_commit_id": {
+				Description: "The id of the last commit that modified the file.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceGitlabRepositoryFileRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+
+	project := d.Get("project").(string)
+	filePath := d.Get("file_path").(string)
+	ref := d.Get("ref").(string)
+
+	log.Printf("[DEBUG] read gitlab repository file %q at %q of project %s", filePath, ref, project)
+
+	repositoryFile, _, err := client.RepositoryFiles.GetFile(project, filePath, &gitlab.GetFileOptions{
+		Ref: gitlab.String(ref),
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	content, err := resourceGitlabRepositoryFileDecodeContent(repositoryFile.Content, d.Get("encoding").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(resourceGitLabRepositoryFileBuildId(project, ref, repositoryFile.FilePath))
+	d.Set("file_path", repositoryFile.FilePath)
+	d.Set("file_name", repositoryFile.FileName)
+	d.Set("size", repositoryFile.Size)
+	d.Set("content", content)
+	d.Set("content_sha256", repositoryFile.SHA256)
+	d.Set("blob_id", repositoryFile.BlobID)
+	d.Set("commit_id", repositoryFile.CommitID)
+	d.Set("last_commit_id", repositoryFile.LastCommitID)
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package gitlab
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func TestAccDataGitlabRepositoryFile_basic(t *testing.T) {
+	testAccCheck(t)
+
+	client := testAccNewClient(t)
+	project := testAccCreateProject(t, client)
+
+	_, _, err := client.RepositoryFiles.CreateFile(project.ID, "VERSION", &gitlab.CreateFileOptions{
+		Branch:        gitlab.String(project.DefaultBranch),
+		Content:       gitlab.String("1.2.3"),
+		CommitMessage: gitlab.String("add version file"),
+	})
+	if err != nil {
+		t.Fatalf("could not create test file: %v", err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "gitlab_repository_file" "base64" {
+  project   = %[1]d
+  file_path = "VERSION"
+  ref       = %[2]q
+}
+
+data "gitlab_repository_file" "text" {
+  project   = %[1]d
+  file_path = "VERSION"
+  ref       = %[2]q
+  encoding  = "text"
+}
+				`, project.ID, project.DefaultBranch),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.gitlab_repository_file.base64", "content", "MS4yLjM="),
+					resource.TestCheckResourceAttr("data.gitlab_repository_file.text", "content", "1.2.3"),
+					resource.TestCheckResourceAttr("data.gitlab_repository_file.text", "file_name", "VERSION"),
+					resource.TestCheckResourceAttr("data.gitlab_repository_file.text", "size", "5"),
+					resource.TestCheckResourceAttrSet("data.gitlab_repository_file.text", "content_sha256"),
+					resource.TestCheckResourceAttrSet("data.gitlab_repository_file.text", "blob_id"),
+					resource.TestCheckResourceAttrSet("data.gitlab_repository_file.text", "commit_id"),
+					resource.TestCheckResourceAttrSet("data.gitlab_repository_file.text", "last_commit_id"),
+				),
+			},
+		},
+	})
+}
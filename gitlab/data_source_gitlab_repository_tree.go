@@ -0,0 +1,161 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/mitchellh/hashstructure"
+	"github.com/xanzy/go-gitlab"
+)
+
+func dataSourceGitlabRepositoryTree() *schema.Resource {
+	return &schema.Resource{
+		Description: "Provides a listing of the files and directories in a given project repository at a given ref.",
+
+		ReadContext: dataSourceGitlabRepositoryTreeRead,
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Description:  "The integer or path with namespace that uniquely identifies the project.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"ref": {
+				Description:  "The name of branch, tag or commit to list the tree of.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"path": {
+				Description: "The path inside the repository to list. Defaults to the root of the repository.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"recursive": {
+				Description: "Whether to list the tree recursively.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"type": {
+				Description:  "Only return entries of this type. Valid values are `blob` (files) and `tree` (directories).",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"blob", "tree"}, false),
+			},
+			"path_regex": {
+				Description:  "Only return entries with a path matching this regular expression.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"tree": {
+				Description: "The list of files and directories, as defined below.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Description: "The SHA of the blob or tree.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"name": {
+							Description: "The name of the file or directory.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"type": {
+							Description: "The type of the entry, either `blob` or `tree`.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"path": {
+							Description: "The full path of the file or directory.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"mode": {
+							Description: "The unix file mode of the entry.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceGitlabRepositoryTreeRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+
+	project := d.Get("project").(string)
+	ref := d.Get("ref").(string)
+	treePath := d.Get("path").(string)
+
+	var pathRegex *regexp.Regexp
+	if v, ok := d.GetOk("path_regex"); ok {
+		pathRegex = regexp.MustCompile(v.(string))
+	}
+	nodeType := d.Get("type").(string)
+
+	options := &gitlab.ListTreeOptions{
+		ListOptions: gitlab.ListOptions{
+			PerPage: 100,
+			Page:    1,
+		},
+		Ref:       gitlab.String(ref),
+		Recursive: gitlab.Bool(d.Get("recursive").(bool)),
+	}
+	if treePath != "" {
+		options.Path = gitlab.String(treePath)
+	}
+
+	// The ID is hashed before paginating, which changes the options, and includes the client side filters.
+	h, err := hashstructure.Hash([]interface{}{*options, nodeType, d.Get("path_regex").(string)}, nil)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] list gitlab repository tree %q at %q of project %s", treePath, ref, project)
+
+	tree := make([]map[string]interface{}, 0)
+	for options.Page != 0 {
+		nodes, resp, err := client.Repositories.ListTree(project, options, gitlab.WithContext(ctx))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		for _, node := range nodes {
+			if nodeType != "" && node.Type != nodeType {
+				continue
+			}
+			if pathRegex != nil && !pathRegex.MatchString(node.Path) {
+				continue
+			}
+
+			tree = append(tree, map[string]interface{}{
+				"id":   node.ID,
+				"name": node.Name,
+				"type": node.Type,
+				"path": node.Path,
+				"mode": node.Mode,
+			})
+		}
+
+		options.Page = resp.NextPage
+	}
+
+	d.SetId(fmt.Sprintf("%s-%d", project, h))
+	if err := d.Set("tree", tree); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package gitlab
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func TestAccDataGitlabRepositoryTree_basic(t *testing.T) {
+	testAccCheck(t)
+
+	client := testAccNewClient(t)
+	project := testAccCreateProject(t, client)
+
+	var actions []*gitlab.CommitActionOptions
+	for _, filePath := range []string{"environments/staging/main.tf", "environments/production/main.tf", "modules/network/main.tf"} {
+		actions = append(actions, &gitlab.CommitActionOptions{
+			Action:   gitlab.FileAction(gitlab.FileCreate),
+			FilePath: gitlab.String(filePath),
+			Content:  gitlab.String("# meow"),
+		})
+	}
+	_, _, err := client.Commits.CreateCommit(project.ID, &gitlab.CreateCommitOptions{
+		Branch:        gitlab.String(project.DefaultBranch),
+		CommitMessage: gitlab.String("add test files"),
+		Actions:       actions,
+	})
+	if err != nil {
+		t.Fatalf("could not create test files: %v", err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "gitlab_repository_tree" "environments" {
+  project = %[1]d
+  ref     = %[2]q
+  path    = "environments"
+  type    = "tree"
+}
+
+data "gitlab_repository_tree" "terraform_files" {
+  project    = %[1]d
+  ref        = %[2]q
+  recursive  = true
+  type       = "blob"
+  path_regex = "\\.tf$"
+}
+				`, project.ID, project.DefaultBranch),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.gitlab_repository_tree.environments", "tree.#", "2"),
+					resource.TestCheckResourceAttr("data.gitlab_repository_tree.environments", "tree.0.name", "production"),
+					resource.TestCheckResourceAttr("data.gitlab_repository_tree.environments", "tree.1.name", "staging"),
+					resource.TestCheckResourceAttr("data.gitlab_repository_tree.terraform_files", "tree.#", "3"),
+				),
+			},
+		},
+	})
+}
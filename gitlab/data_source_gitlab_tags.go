@@ -0,0 +1,136 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/mitchellh/hashstructure"
+	"github.com/xanzy/go-gitlab"
+)
+
+func dataSourceGitlabTags() *schema.Resource {
+	return &schema.Resource{
+		Description: "Provides details about the tags of a given project.",
+
+		ReadContext: dataSourceGitlabTagsRead,
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Description:  "The integer or path with namespace that uniquely identifies the project.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"search": {
+				Description: "Only return tags containing this search term, as supported by the GitLab API. Use `^term` and `term$` to find tags that begin and end with `term` respectively.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"regex": {
+				Description:  "Only return tags with a name matching this regular expression.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"order_by": {
+				Description:  "Order the tags by `name` or `updated`. Defaults to `updated`.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"name", "updated"}, false),
+			},
+			"sort": {
+				Description:  "Sort the tags in `asc` or `desc` order. Defaults to `desc`.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"asc", "desc"}, false),
+			},
+			"tags": {
+				Description: "The list of tags, as defined below.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Description: "The name of the tag.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"message": {
+							Description: "The message of an annotated tag.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"commit_sha": {
+							Description: "The SHA of the commit the tag points to.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceGitlabTagsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	project := d.Get("project").(string)
+
+	var nameRegex *regexp.Regexp
+	if v, ok := d.GetOk("regex"); ok {
+		nameRegex = regexp.MustCompile(v.(string))
+	}
+
+	options := &gitlab.ListTagsOptions{
+		ListOptions: gitlab.ListOptions{
+			PerPage: 100,
+			Page:    1,
+		},
+	}
+	if v, ok := d.GetOk("search"); ok {
+		options.Search = gitlab.String(v.(string))
+	}
+	if v, ok := d.GetOk("order_by"); ok {
+		options.OrderBy = gitlab.String(v.(string))
+	}
+	if v, ok := d.GetOk("sort"); ok {
+		options.Sort = gitlab.String(v.(string))
+	}
+
+	// The ID is hashed before paginating, which changes the options, and includes the client side filter.
+	h, err := hashstructure.Hash([]interface{}{*options, d.Get("regex").(string)}, nil)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] list gitlab tags for project %s", project)
+
+	tags := make([]map[string]interface{}, 0)
+	for options.Page != 0 {
+		page, resp, err := client.Tags.ListTags(project, options, gitlab.WithContext(ctx))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		for _, tag := range page {
+			if nameRegex != nil && !nameRegex.MatchString(tag.Name) {
+				continue
+			}
+			tags = append(tags, flattenGitlabTag(tag))
+		}
+
+		options.Page = resp.NextPage
+	}
+
+	if err := d.Set("tags", tags); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s-%d", project, h))
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package gitlab
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func TestAccDataGitlabTags_regex(t *testing.T) {
+	testAccCheck(t)
+
+	client := testAccNewClient(t)
+	project := testAccCreateProject(t, client)
+
+	for _, name := range []string{"v1.0.0", "v1.1.0", "nightly"} {
+		if _, _, err := client.Tags.CreateTag(project.ID, &gitlab.CreateTagOptions{
+			TagName: gitlab.String(name),
+			Ref:     gitlab.String(project.DefaultBranch),
+		}); err != nil {
+			t.Fatalf("could not create test tag: %v", err)
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "gitlab_tags" "versions" {
+  project  = %d
+  regex    = "^v\\d+\\.\\d+\\.\\d+$"
+  order_by = "name"
+  sort     = "asc"
+}
+				`, project.ID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.gitlab_tags.versions", "tags.#", "2"),
+					resource.TestCheckResourceAttr("data.gitlab_tags.versions", "tags.0.name", "v1.0.0"),
+					resource.TestCheckResourceAttr("data.gitlab_tags.versions", "tags.1.name", "v1.1.0"),
+				),
+			},
+		},
+	})
+}
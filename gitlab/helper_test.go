@@ -1,10 +1,10 @@
 package gitlab
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
-	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
@@ -43,18 +43,16 @@ func testAccCompareGitLabAttribute(attr string, expected, received *schema.Resou
 // Returns true if the acceptance test is running Gitlab EE.
 // Meant to be used as SkipFunc to skip tests that work only on Gitlab CE.
 func isRunningInEE() (bool, error) {
-	if conn, ok := testAccProvider.Meta().(*gitlab.Client); ok {
-		version, _, err := conn.Version.GetVersion()
-		if err != nil {
-			return false, err
-		}
-		if strings.Contains(version.String(), "-ee") {
-			return true, nil
-		}
-	} else {
+	conn, ok := testAccProvider.Meta().(*gitlab.Client)
+	if !ok {
 		return false, errors.New("Provider not initialized, unable to get GitLab connection")
 	}
-	return false, nil
+
+	version, err := getGitlabVersion(context.Background(), conn)
+	if err != nil {
+		return false, err
+	}
+	return version.isEnterpriseEdition(), nil
 }
 
 // Returns true if the acceptance test is running Gitlab CE.
@@ -97,13 +95,13 @@ func testAccNewClient(t *testing.T) *gitlab.Client {
 func testAccCheckEE(t *testing.T, client *gitlab.Client) {
 	t.Helper()
 
-	version, _, err := client.Version.GetVersion()
+	version, err := getGitlabVersion(context.Background(), client)
 	if err != nil {
 		t.Fatalf("could not check GitLab version: %v", err)
 	}
 
-	if !strings.HasSuffix(version.Version, "-ee") {
-		t.Skipf("Test is skipped for non-Enterprise version of GitLab (was %q)", version.String())
+	if !version.isEnterpriseEdition() {
+		t.Skipf("Test is skipped for non-Enterprise version of GitLab (was %q)", version.version)
 	}
 }
 
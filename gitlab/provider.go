@@ -58,58 +58,77 @@ func Provider() *schema.Provider {
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
+			"gitlab_branches":                   dataSourceGitlabBranches(),
+			"gitlab_ci_lint":                    dataSourceGitlabCILint(),
 			"gitlab_group":                      dataSourceGitlabGroup(),
 			"gitlab_group_membership":           dataSourceGitlabGroupMembership(),
 			"gitlab_project":                    dataSourceGitlabProject(),
+			"gitlab_project_environments":       dataSourceGitlabProjectEnvironments(),
 			"gitlab_project_protected_branch":   dataSourceGitlabProjectProtectedBranch(),
 			"gitlab_project_protected_branches": dataSourceGitlabProjectProtectedBranches(),
+			"gitlab_project_protected_tags":     dataSourceGitlabProjectProtectedTags(),
 			"gitlab_projects":                   dataSourceGitlabProjects(),
+			"gitlab_release":                    dataSourceGitlabRelease(),
+			"gitlab_repository_file":            dataSourceGitlabRepositoryFile(),
+			"gitlab_repository_tree":            dataSourceGitlabRepositoryTree(),
+			"gitlab_tags":                       dataSourceGitlabTags(),
 			"gitlab_user":                       dataSourceGitlabUser(),
 			"gitlab_users":                      dataSourceGitlabUsers(),
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
-			"gitlab_branch_protection":          resourceGitlabBranchProtection(),
-			"gitlab_tag_protection":             resourceGitlabTagProtection(),
-			"gitlab_group":                      resourceGitlabGroup(),
-			"gitlab_group_custom_attribute":     resourceGitlabGroupCustomAttribute(),
-			"gitlab_project":                    resourceGitlabProject(),
-			"gitlab_project_custom_attribute":   resourceGitlabProjectCustomAttribute(),
-			"gitlab_label":                      resourceGitlabLabel(),
-			"gitlab_group_label":                resourceGitlabGroupLabel(),
-			"gitlab_pipeline_schedule":          resourceGitlabPipelineSchedule(),
-			"gitlab_pipeline_schedule_variable": resourceGitlabPipelineScheduleVariable(),
-			"gitlab_pipeline_trigger":           resourceGitlabPipelineTrigger(),
-			"gitlab_project_hook":               resourceGitlabProjectHook(),
-			"gitlab_deploy_key":                 resourceGitlabDeployKey(),
-			"gitlab_deploy_key_enable":          resourceGitlabDeployEnableKey(),
-			"gitlab_deploy_token":               resourceGitlabDeployToken(),
-			"gitlab_user":                       resourceGitlabUser(),
-			"gitlab_user_custom_attribute":      resourceGitlabUserCustomAttribute(),
-			"gitlab_project_membership":         resourceGitlabProjectMembership(),
-			"gitlab_group_membership":           resourceGitlabGroupMembership(),
-			"gitlab_project_variable":           resourceGitlabProjectVariable(),
-			"gitlab_group_variable":             resourceGitlabGroupVariable(),
-			"gitlab_project_access_token":       resourceGitlabProjectAccessToken(),
-			"gitlab_project_cluster":            resourceGitlabProjectCluster(),
-			"gitlab_service_slack":              resourceGitlabServiceSlack(),
-			"gitlab_service_jira":               resourceGitlabServiceJira(),
-			"gitlab_service_microsoft_teams":    resourceGitlabServiceMicrosoftTeams(),
-			"gitlab_service_github":             resourceGitlabServiceGithub(),
-			"gitlab_service_pipelines_email":    resourceGitlabServicePipelinesEmail(),
-			"gitlab_project_share_group":        resourceGitlabProjectShareGroup(),
-			"gitlab_group_cluster":              resourceGitlabGroupCluster(),
-			"gitlab_group_ldap_link":            resourceGitlabGroupLdapLink(),
-			"gitlab_instance_cluster":           resourceGitlabInstanceCluster(),
-			"gitlab_project_mirror":             resourceGitlabProjectMirror(),
-			"gitlab_project_level_mr_approvals": resourceGitlabProjectLevelMRApprovals(),
-			"gitlab_project_approval_rule":      resourceGitlabProjectApprovalRule(),
-			"gitlab_instance_variable":          resourceGitlabInstanceVariable(),
-			"gitlab_project_freeze_period":      resourceGitlabProjectFreezePeriod(),
-			"gitlab_group_share_group":          resourceGitlabGroupShareGroup(),
-			"gitlab_project_badge":              resourceGitlabProjectBadge(),
-			"gitlab_group_badge":                resourceGitlabGroupBadge(),
-			"gitlab_repository_file":            resourceGitLabRepositoryFile(),
+			"gitlab_branch":                        resourceGitlabBranch(),
+			"gitlab_branch_protection":             resourceGitlabBranchProtection(),
+			"gitlab_group_branch_protection":       resourceGitlabGroupBranchProtection(),
+			"gitlab_project_environment":           resourceGitlabProjectEnvironment(),
+			"gitlab_project_protected_environment": resourceGitlabProjectProtectedEnvironment(),
+			"gitlab_group_protected_environment":   resourceGitlabGroupProtectedEnvironment(),
+			"gitlab_tag":                           resourceGitlabTag(),
+			"gitlab_tag_protection":                resourceGitlabTagProtection(),
+			"gitlab_group":                         resourceGitlabGroup(),
+			"gitlab_group_custom_attribute":        resourceGitlabGroupCustomAttribute(),
+			"gitlab_project":                       resourceGitlabProject(),
+			"gitlab_project_custom_attribute":      resourceGitlabProjectCustomAttribute(),
+			"gitlab_label":                         resourceGitlabLabel(),
+			"gitlab_group_label":                   resourceGitlabGroupLabel(),
+			"gitlab_pipeline_schedule":             resourceGitlabPipelineSchedule(),
+			"gitlab_pipeline_schedule_variable":    resourceGitlabPipelineScheduleVariable(),
+			"gitlab_pipeline_trigger":              resourceGitlabPipelineTrigger(),
+			"gitlab_pipeline_run":                  resourceGitlabPipelineRun(),
+			"gitlab_project_export":                resourceGitlabProjectExport(),
+			"gitlab_project_import":                resourceGitlabProjectImport(),
+			"gitlab_project_hook":                  resourceGitlabProjectHook(),
+			"gitlab_deploy_key":                    resourceGitlabDeployKey(),
+			"gitlab_deploy_key_enable":             resourceGitlabDeployEnableKey(),
+			"gitlab_deploy_token":                  resourceGitlabDeployToken(),
+			"gitlab_user":                          resourceGitlabUser(),
+			"gitlab_user_custom_attribute":         resourceGitlabUserCustomAttribute(),
+			"gitlab_project_membership":            resourceGitlabProjectMembership(),
+			"gitlab_group_membership":              resourceGitlabGroupMembership(),
+			"gitlab_project_variable":              resourceGitlabProjectVariable(),
+			"gitlab_group_variable":                resourceGitlabGroupVariable(),
+			"gitlab_project_access_token":          resourceGitlabProjectAccessToken(),
+			"gitlab_project_cluster":               resourceGitlabProjectCluster(),
+			"gitlab_service_slack":                 resourceGitlabServiceSlack(),
+			"gitlab_service_jira":                  resourceGitlabServiceJira(),
+			"gitlab_service_microsoft_teams":       resourceGitlabServiceMicrosoftTeams(),
+			"gitlab_service_github":                resourceGitlabServiceGithub(),
+			"gitlab_service_pipelines_email":       resourceGitlabServicePipelinesEmail(),
+			"gitlab_project_share_group":           resourceGitlabProjectShareGroup(),
+			"gitlab_group_cluster":                 resourceGitlabGroupCluster(),
+			"gitlab_group_ldap_link":               resourceGitlabGroupLdapLink(),
+			"gitlab_instance_cluster":              resourceGitlabInstanceCluster(),
+			"gitlab_project_mirror":                resourceGitlabProjectMirror(),
+			"gitlab_project_level_mr_approvals":    resourceGitlabProjectLevelMRApprovals(),
+			"gitlab_project_approval_rule":         resourceGitlabProjectApprovalRule(),
+			"gitlab_instance_variable":             resourceGitlabInstanceVariable(),
+			"gitlab_project_freeze_period":         resourceGitlabProjectFreezePeriod(),
+			"gitlab_group_share_group":             resourceGitlabGroupShareGroup(),
+			"gitlab_project_badge":                 resourceGitlabProjectBadge(),
+			"gitlab_group_badge":                   resourceGitlabGroupBadge(),
+			"gitlab_repository_file":               resourceGitLabRepositoryFile(),
+			"gitlab_repository_directory":          resourceGitlabRepositoryDirectory(),
+			"gitlab_release":                       resourceGitlabRelease(),
 		},
 	}
 
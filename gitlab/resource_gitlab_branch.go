@@ -0,0 +1,198 @@
+package gitlab
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+var resourceGitlabBranchComputedSchema = map[string]*schema.Schema{
+	"commit_sha": {
+		Description: "The SHA of the commit the branch points to.",
+		Type:        schema.TypeString,
+		Computed:    true,
+	},
+	"protected": {
+		Description: "Whether the branch is protected.",
+		Type:        schema.TypeBool,
+		Computed:    true,
+	},
+	"merged": {
+		Description: "Whether the branch is merged into the default branch.",
+		Type:        schema.TypeBool,
+		Computed:    true,
+	},
+	"default": {
+		Description: "Whether the branch is the default branch of the project.",
+		Type:        schema.TypeBool,
+		Computed:    true,
+	},
+	"can_push": {
+		Description: "Whether the current user can push to the branch.",
+		Type:        schema.TypeBool,
+		Computed:    true,
+	},
+	"developers_can_push": {
+		Description: "Whether developers can push to the branch.",
+		Type:        schema.TypeBool,
+		Computed:    true,
+	},
+	"developers_can_merge": {
+		Description: "Whether developers can merge into the branch.",
+		Type:        schema.TypeBool,
+		Computed:    true,
+	},
+	"web_url": {
+		Description: "The URL of the branch in the web interface.",
+		Type:        schema.TypeString,
+		Computed:    true,
+	},
+}
+
+func resourceGitlabBranch() *schema.Resource {
+	s := map[string]*schema.Schema{
+		"project": {
+			Description: "The ID or full path of the project.",
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+		},
+		"name": {
+			Description: "The name of the branch.",
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+		},
+		"ref": {
+			Description: "The branch name or commit SHA to create the branch from. It is only used during creation and is not read back, so it is ignored for imported branches.",
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+				// The ref is unknown for imported branches
+				return old == "" && d.Id() != ""
+			},
+		},
+		"keep_on_destroy": {
+			Description: "Do not delete the branch from the repository when the resource is destroyed, only remove it from the state.",
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+		},
+	}
+	for k, v := range resourceGitlabBranchComputedSchema {
+		s[k] = v
+	}
+
+	return &schema.Resource{
+		Description: "This resource allows you to create and manage branches of a project repository.",
+
+		CreateContext: resourceGitlabBranchCreate,
+		ReadContext:   resourceGitlabBranchRead,
+		UpdateContext: resourceGitlabBranchUpdate,
+		DeleteContext: resourceGitlabBranchDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: s,
+	}
+}
+
+func resourceGitlabBranchCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	project := d.Get("project").(string)
+
+	options := &gitlab.CreateBranchOptions{
+		Branch: gitlab.String(d.Get("name").(string)),
+		Ref:    gitlab.String(d.Get("ref").(string)),
+	}
+
+	log.Printf("[DEBUG] create gitlab branch %q from %q for project %s", *options.Branch, *options.Ref, project)
+
+	branch, _, err := client.Branches.CreateBranch(project, options, gitlab.WithContext(ctx))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(buildTwoPartID(&project, &branch.Name))
+
+	return resourceGitlabBranchRead(ctx, d, meta)
+}
+
+func resourceGitlabBranchRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	project, name, err := parseTwoPartID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] read gitlab branch %q for project %s", name, project)
+
+	branch, _, err := client.Branches.GetBranch(project, name, gitlab.WithContext(ctx))
+	if err != nil {
+		if is404(err) {
+			log.Printf("[DEBUG] gitlab branch %q for project %s not found, removing from state", name, project)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	d.Set("project", project)
+	d.Set("name", branch.Name)
+	for k, v := range flattenGitlabBranch(branch) {
+		if k == "name" {
+			continue
+		}
+		d.Set(k, v)
+	}
+
+	return nil
+}
+
+func resourceGitlabBranchUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// Only keep_on_destroy can be updated, which is not sent to the API.
+	return resourceGitlabBranchRead(ctx, d, meta)
+}
+
+func resourceGitlabBranchDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	project, name, err := parseTwoPartID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.Get("keep_on_destroy").(bool) {
+		log.Printf("[DEBUG] keep gitlab branch %q for project %s, only removing it from state", name, project)
+		return nil
+	}
+
+	log.Printf("[DEBUG] delete gitlab branch %q for project %s", name, project)
+
+	if _, err := client.Branches.DeleteBranch(project, name, gitlab.WithContext(ctx)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func flattenGitlabBranch(branch *gitlab.Branch) map[string]interface{} {
+	values := map[string]interface{}{
+		"name":                 branch.Name,
+		"protected":            branch.Protected,
+		"merged":               branch.Merged,
+		"default":              branch.Default,
+		"can_push":             branch.CanPush,
+		"developers_can_push":  branch.DevelopersCanPush,
+		"developers_can_merge": branch.DevelopersCanMerge,
+		"web_url":              branch.WebURL,
+		"commit_sha":           "",
+	}
+	if branch.Commit != nil {
+		values["commit_sha"] = branch.Commit.ID
+	}
+	return values
+}
@@ -2,8 +2,12 @@ package gitlab
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
+	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -35,17 +39,108 @@ var (
 			},
 		},
 	}
+
+	allowedToPushElem = &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"access_level": {
+				Description: "Level of access.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"access_level_description": {
+				Description: "Readable description of level of access.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"user_id": {
+				Description: "The ID of a GitLab user allowed to push. Mutually exclusive with `group_id` and `deploy_key_id`.",
+				Type:        schema.TypeInt,
+				Optional:    true,
+			},
+			"group_id": {
+				Description: "The ID of a GitLab group allowed to push. Mutually exclusive with `user_id` and `deploy_key_id`.",
+				Type:        schema.TypeInt,
+				Optional:    true,
+			},
+			"deploy_key_id": {
+				Description: "The ID of a GitLab deploy key allowed to push. The deploy key must be enabled for the project and have write access. Mutually exclusive with `user_id` and `group_id`.",
+				Type:        schema.TypeInt,
+				Optional:    true,
+			},
+		},
+	}
 )
 
-func resourceGitlabBranchProtection() *schema.Resource {
-	acceptedAccessLevels := make([]string, 0, len(accessLevelID))
+// gitlabBranchAccessDescription extends the branch access description of go-gitlab with its ID and deploy key.
+type gitlabBranchAccessDescription struct {
+	gitlab.BranchAccessDescription
+	ID          int `json:"id"`
+	DeployKeyID int `json:"deploy_key_id"`
+}
 
-	for k := range accessLevelID {
-		acceptedAccessLevels = append(acceptedAccessLevels, k)
+// gitlabProtectedBranch mirrors the protected branch of go-gitlab with the extended access descriptions.
+type gitlabProtectedBranch struct {
+	ID                        int                              `json:"id"`
+	Name                      string                           `json:"name"`
+	PushAccessLevels          []*gitlabBranchAccessDescription `json:"push_access_levels"`
+	MergeAccessLevels         []*gitlabBranchAccessDescription `json:"merge_access_levels"`
+	UnprotectAccessLevels     []*gitlabBranchAccessDescription `json:"unprotect_access_levels"`
+	AllowForcePush            bool                             `json:"allow_force_push"`
+	CodeOwnerApprovalRequired bool                             `json:"code_owner_approval_required"`
+}
+
+// gitlabBranchPermissionChange adds, changes or removes (with Destroy) a single access level of a protected branch.
+type gitlabBranchPermissionChange struct {
+	ID          *int                     `json:"id,omitempty"`
+	UserID      *int                     `json:"user_id,omitempty"`
+	GroupID     *int                     `json:"group_id,omitempty"`
+	DeployKeyID *int                     `json:"deploy_key_id,omitempty"`
+	AccessLevel *gitlab.AccessLevelValue `json:"access_level,omitempty"`
+	Destroy     *bool                    `json:"_destroy,omitempty"`
+}
+
+// gitlabUpdateProtectedBranchOptions are the options of the protected branch PATCH API, which go-gitlab doesn't support yet.
+type gitlabUpdateProtectedBranchOptions struct {
+	AllowForcePush            *bool                           `json:"allow_force_push,omitempty"`
+	CodeOwnerApprovalRequired *bool                           `json:"code_owner_approval_required,omitempty"`
+	AllowedToPush             []*gitlabBranchPermissionChange `json:"allowed_to_push,omitempty"`
+	AllowedToMerge            []*gitlabBranchPermissionChange `json:"allowed_to_merge,omitempty"`
+	AllowedToUnprotect        []*gitlabBranchPermissionChange `json:"allowed_to_unprotect,omitempty"`
+}
+
+// isEmpty reports whether the options don't change anything.
+func (o *gitlabUpdateProtectedBranchOptions) isEmpty() bool {
+	return o.AllowForcePush == nil && o.CodeOwnerApprovalRequired == nil &&
+		len(o.AllowedToPush) == 0 && len(o.AllowedToMerge) == 0 && len(o.AllowedToUnprotect) == 0
+}
+
+func resourceGitlabBranchProtection() *schema.Resource {
+	resourceSchema := schemaBranchProtectionSettings()
+	resourceSchema["project"] = &schema.Schema{
+		Description: "The id of the project.",
+		Type:        schema.TypeString,
+		Required:    true,
+		ForceNew:    true,
+	}
+	resourceSchema["allowed_to_push"] = schemaAllowedToPush()
+	resourceSchema["adopt_existing"] = &schema.Schema{
+		Description: "Adopt the protection of the branch if it's already protected, e.g. the default branch which GitLab protects when creating a project, instead of failing. " +
+			"If the existing protection differs from the configured one, the branch is protected again with the configured settings.",
+		Type:     schema.TypeBool,
+		Optional: true,
+		Default:  false,
 	}
+	resourceSchema["matching_branches"] = &schema.Schema{
+		Description: "The names of the existing branches which are protected by this branch protection.",
+		Type:        schema.TypeList,
+		Computed:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+	}
+
 	return &schema.Resource{
 		Description: "This resource allows you to protect a specific branch by an access level so that the user with less access level cannot Merge/Push to the branch.\n\n" +
-			"-> The `allowed_to_push`, `allowed_to_merge` and `code_owner_approval_required` arguments require a GitLab Premium account or above.  Please refer to [Gitlab API documentation](https://docs.gitlab.com/ee/api/protected_branches.html) for further information.",
+			"-> The `allowed_to_push`, `allowed_to_merge`, `unprotect_access_level` and `code_owner_approval_required` arguments require a GitLab Premium account or above.  Please refer to [Gitlab API documentation](https://docs.gitlab.com/ee/api/protected_branches.html) for further information.\n\n" +
+			"-> Changes are applied in place with GitLab 15.6 or later. With older versions, the branch is unprotected and protected again with the new settings.",
 
 		CreateContext: resourceGitlabBranchProtectionCreate,
 		ReadContext:   resourceGitlabBranchProtectionRead,
@@ -54,46 +149,64 @@ func resourceGitlabBranchProtection() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
-		Schema: map[string]*schema.Schema{
-			"project": {
-				Description: "The id of the project.",
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-			},
-			"branch": {
-				Description: "Name of the branch.",
-				Type:        schema.TypeString,
-				ForceNew:    true,
-				Required:    true,
-			},
-			"merge_access_level": {
-				Description:      "Access levels allowed to merge. Valid values are: `no one`, `developer`, `maintainer`, `admin`.",
-				Type:             schema.TypeString,
-				ValidateDiagFunc: validateValueFunc(acceptedAccessLevels),
-				Required:         true,
-				ForceNew:         true,
-			},
-			"push_access_level": {
-				Description:      "Access levels allowed to push. Valid values are: `no one`, `developer`, `maintainer`, `admin`.",
-				Type:             schema.TypeString,
-				ValidateDiagFunc: validateValueFunc(acceptedAccessLevels),
-				Required:         true,
-				ForceNew:         true,
-			},
-			"allowed_to_push":  schemaAllowedTo(),
-			"allowed_to_merge": schemaAllowedTo(),
-			"code_owner_approval_required": {
-				Description: "Can be set to true to require code owner approval before merging.",
-				Type:        schema.TypeBool,
-				Optional:    true,
-				Default:     false,
-			},
-			"branch_protection_id": {
-				Description: "The ID of the branch protection (not the branch name).",
-				Type:        schema.TypeInt,
-				Computed:    true,
-			},
+		Schema: resourceSchema,
+	}
+}
+
+// schemaBranchProtectionSettings returns the schema of the branch name, access levels and settings
+// shared by project and group branch protections.
+func schemaBranchProtectionSettings() map[string]*schema.Schema {
+	acceptedAccessLevels := make([]string, 0, len(accessLevelID))
+
+	for k := range accessLevelID {
+		acceptedAccessLevels = append(acceptedAccessLevels, k)
+	}
+
+	return map[string]*schema.Schema{
+		"branch": {
+			Description:  "Name of the branch or wildcard, e.g. `release/*`. A `*` matches any sequence of characters, including `/`.",
+			Type:         schema.TypeString,
+			ForceNew:     true,
+			Required:     true,
+			ValidateFunc: validateProtectedBranchNameFunc,
+		},
+		"merge_access_level": {
+			Description:      "Access levels allowed to merge. Valid values are: `no one`, `developer`, `maintainer`, `admin`.",
+			Type:             schema.TypeString,
+			ValidateDiagFunc: validateValueFunc(acceptedAccessLevels),
+			Required:         true,
+		},
+		"push_access_level": {
+			Description:      "Access levels allowed to push. Valid values are: `no one`, `developer`, `maintainer`, `admin`.",
+			Type:             schema.TypeString,
+			ValidateDiagFunc: validateValueFunc(acceptedAccessLevels),
+			Required:         true,
+		},
+		"unprotect_access_level": {
+			Description:      "Access levels allowed to unprotect. Valid values are: `developer`, `maintainer`, `admin`. Defaults to `maintainer`.",
+			Type:             schema.TypeString,
+			ValidateDiagFunc: validateValueFunc(acceptedAccessLevels),
+			Optional:         true,
+			Computed:         true,
+		},
+		"allow_force_push": {
+			Description: "Can be set to true to allow users with push access to force push.",
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+		},
+		"allowed_to_push":  schemaAllowedTo(),
+		"allowed_to_merge": schemaAllowedTo(),
+		"code_owner_approval_required": {
+			Description: "Can be set to true to require code owner approval before merging.",
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+		},
+		"branch_protection_id": {
+			Description: "The ID of the branch protection (not the branch name).",
+			Type:        schema.TypeInt,
+			Computed:    true,
 		},
 	}
 }
@@ -105,36 +218,65 @@ func resourceGitlabBranchProtectionCreate(ctx context.Context, d *schema.Resourc
 
 	log.Printf("[DEBUG] create gitlab branch protection on branch %q for project %s", branch, project)
 
+	options := expandProtectRepositoryBranchesOptions(d)
+
+	var existing *gitlabProtectedBranch
 	if d.IsNewResource() {
-		existing, resp, err := client.ProtectedBranches.GetProtectedBranch(project, branch, gitlab.WithContext(ctx))
-		if err != nil && resp.StatusCode != http.StatusNotFound {
+		var err error
+		existing, err = getGitlabProtectedBranch(ctx, client, project, branch)
+		if err != nil && !is404(err) {
 			return diag.Errorf("error looking up protected branch %q on project %q: %v", branch, project, err)
 		}
-		if resp.StatusCode != http.StatusNotFound {
-			return diag.Errorf("protected branch %q on project %q already exists: %+v", branch, project, *existing)
+		if existing != nil {
+			if !d.Get("adopt_existing").(bool) {
+				return diag.Errorf("protected branch %q on project %q already exists: %+v", branch, project, *existing)
+			}
+
+			if protectedBranchMatchesOptions(existing, options) {
+				log.Printf("[DEBUG] adopt existing gitlab branch protection on branch %q for project %s", branch, project)
+				d.SetId(buildTwoPartID(&project, &existing.Name))
+				return resourceGitlabBranchProtectionRead(ctx, d, meta)
+			}
+
+			isPatchSupported, err := isGitLabVersionAtLeast(client, "15.6")()
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
+			// The existing protection is updated in place, so that it has exactly the configured settings.
+			if isPatchSupported {
+				log.Printf("[DEBUG] update existing gitlab branch protection on branch %q for project %s to adopt it", branch, project)
+				updated, err := updateGitlabProtectedBranch(ctx, client, project, branch, expandUpdateProtectedBranchOptions(d, existing, func(...string) bool { return true }))
+				if err != nil {
+					return diag.Errorf("error updating existing protected branch %q on project %q: %v", branch, project, err)
+				}
+
+				d.SetId(buildTwoPartID(&project, &updated.Name))
+
+				if !updated.CodeOwnerApprovalRequired && *options.CodeOwnerApprovalRequired {
+					return diag.Errorf("feature unavailable: code owner approvals")
+				}
+
+				return resourceGitlabBranchProtectionRead(ctx, d, meta)
+			}
+
+			// Otherwise the existing protection is replaced.
+			log.Printf("[DEBUG] unprotect existing gitlab branch protection on branch %q for project %s to adopt it", branch, project)
+			if _, err := client.ProtectedBranches.UnprotectRepositoryBranches(project, branch, gitlab.WithContext(ctx)); err != nil {
+				return diag.Errorf("error unprotecting existing protected branch %q on project %q: %v", branch, project, err)
+			}
 		}
 	}
 
-	mergeAccessLevel := accessLevelID[d.Get("merge_access_level").(string)]
-	pushAccessLevel := accessLevelID[d.Get("push_access_level").(string)]
-	codeOwnerApprovalRequired := d.Get("code_owner_approval_required").(bool)
-
-	allowedToPush := expandBranchPermissionOptions(d.Get("allowed_to_push").(*schema.Set).List())
-	allowedToMerge := expandBranchPermissionOptions(d.Get("allowed_to_merge").(*schema.Set).List())
-
-	pb, _, err := client.ProtectedBranches.ProtectRepositoryBranches(project, &gitlab.ProtectRepositoryBranchesOptions{
-		Name:                      &branch,
-		PushAccessLevel:           &pushAccessLevel,
-		MergeAccessLevel:          &mergeAccessLevel,
-		AllowedToPush:             &allowedToPush,
-		AllowedToMerge:            &allowedToMerge,
-		CodeOwnerApprovalRequired: &codeOwnerApprovalRequired,
-	}, gitlab.WithContext(ctx))
+	pb, _, err := client.ProtectedBranches.ProtectRepositoryBranches(project, options, gitlab.WithContext(ctx))
 	if err != nil {
+		if existing != nil {
+			return restoreGitlabBranchProtection(ctx, client, project, branch, existing, err)
+		}
 		return diag.Errorf("error protecting branch %q on project %q: %v", branch, project, err)
 	}
 
-	if !pb.CodeOwnerApprovalRequired && codeOwnerApprovalRequired {
+	if !pb.CodeOwnerApprovalRequired && *options.CodeOwnerApprovalRequired {
 		return diag.Errorf("feature unavailable: code owner approvals")
 	}
 
@@ -153,7 +295,7 @@ func resourceGitlabBranchProtectionRead(ctx context.Context, d *schema.ResourceD
 	log.Printf("[DEBUG] read gitlab branch protection for project %s, branch %s", project, branch)
 
 	// Get protected branch by project ID/path and branch name
-	pb, _, err := client.ProtectedBranches.GetProtectedBranch(project, branch, gitlab.WithContext(ctx))
+	pb, err := getGitlabProtectedBranch(ctx, client, project, branch)
 	if err != nil {
 		log.Printf("[DEBUG] failed to read gitlab branch protection for project %s, branch %s: %s", project, branch, err)
 		d.SetId("")
@@ -163,65 +305,81 @@ func resourceGitlabBranchProtectionRead(ctx context.Context, d *schema.ResourceD
 	d.Set("project", project)
 	d.Set("branch", pb.Name)
 
-	pushAccessLevels := convertAllowedAccessLevelsToBranchAccessDescriptions(pb.PushAccessLevels)
-	if len(pushAccessLevels) > 0 {
-		if err := d.Set("push_access_level", pushAccessLevels[0].AccessLevel); err != nil {
-			return diag.Errorf("error setting push_access_level: %v", err)
-		}
+	if err := setProtectedBranchToState(d, pb); err != nil {
+		return diag.FromErr(err)
 	}
 
-	mergeAccessLevels := convertAllowedAccessLevelsToBranchAccessDescriptions(pb.MergeAccessLevels)
-	if len(mergeAccessLevels) > 0 {
-		if err := d.Set("merge_access_level", mergeAccessLevels[0].AccessLevel); err != nil {
-			return diag.Errorf("error setting merge_access_level: %v", err)
-		}
-	}
+	d.Set("branch_protection_id", pb.ID)
 
-	// lintignore: R004 // TODO: Resolve this tfproviderlint issue
-	if err := d.Set("allowed_to_push", convertAllowedToToBranchAccessDescriptions(pb.PushAccessLevels)); err != nil {
-		return diag.Errorf("error setting allowed_to_push: %v", err)
-	}
-	// lintignore: R004 // TODO: Resolve this tfproviderlint issue
-	if err := d.Set("allowed_to_merge", convertAllowedToToBranchAccessDescriptions(pb.MergeAccessLevels)); err != nil {
-		return diag.Errorf("error setting allowed_to_merge: %v", err)
+	matchingBranches, err := listGitlabProtectedBranchMatches(ctx, client, project, pb.Name)
+	if err != nil {
+		return diag.Errorf("error listing branches of project %s: %v", project, err)
 	}
-
-	if err := d.Set("code_owner_approval_required", pb.CodeOwnerApprovalRequired); err != nil {
-		return diag.Errorf("error setting code_owner_approval_required: %v", err)
+	if err := d.Set("matching_branches", matchingBranches); err != nil {
+		return diag.Errorf("error setting matching_branches: %v", err)
 	}
 
-	d.Set("branch_protection_id", pb.ID)
-
 	d.SetId(buildTwoPartID(&project, &pb.Name))
 
 	return nil
 }
 
 func resourceGitlabBranchProtectionUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	// NOTE: At the time of writing, the only value that does not force re-creation is code_owner_approval_required,
-	// so therefore that is the only update that needs to be handled.
-
 	client := meta.(*gitlab.Client)
 	project := d.Get("project").(string)
 	branch := d.Get("branch").(string)
 	codeOwnerApprovalRequired := d.Get("code_owner_approval_required").(bool)
 
+	// adopt_existing only affects the creation of the resource
+	if !d.HasChangeExcept("adopt_existing") {
+		return resourceGitlabBranchProtectionRead(ctx, d, meta)
+	}
+
 	log.Printf("[DEBUG] update gitlab branch protection for project %s, branch %s", project, branch)
 
-	options := &gitlab.RequireCodeOwnerApprovalsOptions{
-		CodeOwnerApprovalRequired: &codeOwnerApprovalRequired,
+	isPatchSupported, err := isGitLabVersionAtLeast(client, "15.6")()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	pb, err := getGitlabProtectedBranch(ctx, client, project, branch)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
-	if _, err := client.ProtectedBranches.RequireCodeOwnerApprovals(project, branch, options, gitlab.WithContext(ctx)); err != nil {
-		// The user might be running a version of GitLab that does not support this feature.
-		// We enhance the generic 404 error with a more informative message.
-		if errResponse, ok := err.(*gitlab.ErrorResponse); ok && errResponse.Response.StatusCode == 404 {
-			return diag.Errorf("feature unavailable: code owner approvals: %v", err)
+	if !isPatchSupported {
+		// The protected branch can't be changed in place, so it's protected again with the new settings.
+		log.Printf("[DEBUG] replace gitlab branch protection for project %s, branch %s", project, branch)
+		if _, err := client.ProtectedBranches.UnprotectRepositoryBranches(project, branch, gitlab.WithContext(ctx)); err != nil {
+			return diag.FromErr(err)
+		}
+
+		updated, _, err := client.ProtectedBranches.ProtectRepositoryBranches(project, expandProtectRepositoryBranchesOptions(d), gitlab.WithContext(ctx))
+		if err != nil {
+			return restoreGitlabBranchProtection(ctx, client, project, branch, pb, err)
+		}
+
+		if !updated.CodeOwnerApprovalRequired && codeOwnerApprovalRequired {
+			return diag.Errorf("feature unavailable: code owner approvals")
 		}
 
+		return resourceGitlabBranchProtectionRead(ctx, d, meta)
+	}
+
+	options := expandUpdateProtectedBranchOptions(d, pb, d.HasChanges)
+	if options.isEmpty() {
+		return resourceGitlabBranchProtectionRead(ctx, d, meta)
+	}
+
+	updated, err := updateGitlabProtectedBranch(ctx, client, project, branch, options)
+	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	if !updated.CodeOwnerApprovalRequired && codeOwnerApprovalRequired {
+		return diag.Errorf("feature unavailable: code owner approvals")
+	}
+
 	return resourceGitlabBranchProtectionRead(ctx, d, meta)
 }
 
@@ -240,6 +398,102 @@ func resourceGitlabBranchProtectionDelete(ctx context.Context, d *schema.Resourc
 	return nil
 }
 
+// setProtectedBranchToState sets the access levels and settings shared by project and group branch protections.
+func setProtectedBranchToState(d *schema.ResourceData, pb *gitlabProtectedBranch) error {
+	pushAccessLevels := convertAllowedAccessLevelsToBranchAccessDescriptions(pb.PushAccessLevels)
+	if len(pushAccessLevels) > 0 {
+		if err := d.Set("push_access_level", pushAccessLevels[0].AccessLevel); err != nil {
+			return fmt.Errorf("error setting push_access_level: %v", err)
+		}
+	}
+
+	mergeAccessLevels := convertAllowedAccessLevelsToBranchAccessDescriptions(pb.MergeAccessLevels)
+	if len(mergeAccessLevels) > 0 {
+		if err := d.Set("merge_access_level", mergeAccessLevels[0].AccessLevel); err != nil {
+			return fmt.Errorf("error setting merge_access_level: %v", err)
+		}
+	}
+
+	unprotectAccessLevels := convertAllowedAccessLevelsToBranchAccessDescriptions(pb.UnprotectAccessLevels)
+	if len(unprotectAccessLevels) > 0 {
+		if err := d.Set("unprotect_access_level", unprotectAccessLevels[0].AccessLevel); err != nil {
+			return fmt.Errorf("error setting unprotect_access_level: %v", err)
+		}
+	}
+
+	// lintignore: R004 // TODO: Resolve this tfproviderlint issue
+	if err := d.Set("allowed_to_push", convertAllowedToToBranchAccessDescriptions(pb.PushAccessLevels)); err != nil {
+		return fmt.Errorf("error setting allowed_to_push: %v", err)
+	}
+	// lintignore: R004 // TODO: Resolve this tfproviderlint issue
+	if err := d.Set("allowed_to_merge", convertAllowedToToBranchAccessDescriptions(pb.MergeAccessLevels)); err != nil {
+		return fmt.Errorf("error setting allowed_to_merge: %v", err)
+	}
+
+	if err := d.Set("allow_force_push", pb.AllowForcePush); err != nil {
+		return fmt.Errorf("error setting allow_force_push: %v", err)
+	}
+
+	if err := d.Set("code_owner_approval_required", pb.CodeOwnerApprovalRequired); err != nil {
+		return fmt.Errorf("error setting code_owner_approval_required: %v", err)
+	}
+
+	return nil
+}
+
+// expandUpdateProtectedBranchOptions computes the changes to the given protected branch from the changes of the resource.
+// Only the settings of the keys for which hasChanges is true are included.
+func expandUpdateProtectedBranchOptions(d *schema.ResourceData, pb *gitlabProtectedBranch, hasChanges func(keys ...string) bool) *gitlabUpdateProtectedBranchOptions {
+	options := &gitlabUpdateProtectedBranchOptions{}
+
+	if hasChanges("allow_force_push") {
+		options.AllowForcePush = gitlab.Bool(d.Get("allow_force_push").(bool))
+	}
+
+	if hasChanges("code_owner_approval_required") {
+		options.CodeOwnerApprovalRequired = gitlab.Bool(d.Get("code_owner_approval_required").(bool))
+	}
+
+	if hasChanges("push_access_level", "allowed_to_push") {
+		options.AllowedToPush = expandBranchPermissionChanges(
+			pb.PushAccessLevels,
+			gitlab.AccessLevel(accessLevelID[d.Get("push_access_level").(string)]),
+			expandBranchPermissionOptions(d.Get("allowed_to_push").(*schema.Set).List()),
+		)
+	}
+
+	if hasChanges("merge_access_level", "allowed_to_merge") {
+		options.AllowedToMerge = expandBranchPermissionChanges(
+			pb.MergeAccessLevels,
+			gitlab.AccessLevel(accessLevelID[d.Get("merge_access_level").(string)]),
+			expandBranchPermissionOptions(d.Get("allowed_to_merge").(*schema.Set).List()),
+		)
+	}
+
+	if v, ok := d.GetOk("unprotect_access_level"); ok && hasChanges("unprotect_access_level") {
+		options.AllowedToUnprotect = expandBranchPermissionChanges(
+			roleBranchAccessDescriptions(pb.UnprotectAccessLevels),
+			gitlab.AccessLevel(accessLevelID[v.(string)]),
+			nil,
+		)
+	}
+
+	return options
+}
+
+// restoreGitlabBranchProtection protects the branch again with the previous settings, after protecting it with the new ones failed,
+// and returns the error of protecting the branch.
+func restoreGitlabBranchProtection(ctx context.Context, client *gitlab.Client, project string, branch string, previous *gitlabProtectedBranch, protectErr error) diag.Diagnostics {
+	// Don't leave the branch unprotected, but restore the previous settings.
+	log.Printf("[DEBUG] restore previous gitlab branch protection for project %s, branch %s", project, branch)
+	options := expandProtectRepositoryBranchesOptionsFrom(previous)
+	options.Name = gitlab.String(branch)
+	if _, _, err := client.ProtectedBranches.ProtectRepositoryBranches(project, options, gitlab.WithContext(ctx)); err != nil {
+		return diag.Errorf("error protecting branch %q on project %q: %v; the branch is now UNPROTECTED, restoring the previous protection failed: %v", branch, project, protectErr, err)
+	}
+	return diag.Errorf("error protecting branch %q on project %q, the previous protection has been restored: %v", branch, project, protectErr)
+}
+
 func projectAndBranchFromID(id string) (string, string, error) {
 	project, branch, err := parseTwoPartID(id)
 
@@ -249,6 +503,106 @@ func projectAndBranchFromID(id string) (string, string, error) {
 	return project, branch, err
 }
 
+// protectedBranchNameMatches reports whether the branch is protected by a protected branch with the given name,
+// which may contain `*` wildcards. Like GitLab, a wildcard matches any sequence of characters, including `/`.
+func protectedBranchNameMatches(protectedBranchName string, branch string) bool {
+	if !strings.Contains(protectedBranchName, "*") {
+		return protectedBranchName == branch
+	}
+
+	parts := strings.Split(protectedBranchName, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$").MatchString(branch)
+}
+
+// listGitlabProtectedBranchMatches returns the names of the existing branches which are protected by the protected branch name.
+// Only names with wildcards require listing branches, narrowed down by the part before the first wildcard.
+func listGitlabProtectedBranchMatches(ctx context.Context, client *gitlab.Client, project interface{}, protectedBranchName string) ([]string, error) {
+	matches := make([]string, 0)
+
+	if !strings.Contains(protectedBranchName, "*") {
+		if _, _, err := client.Branches.GetBranch(project, protectedBranchName, gitlab.WithContext(ctx)); err != nil {
+			if is404(err) {
+				return matches, nil
+			}
+			return nil, err
+		}
+		return append(matches, protectedBranchName), nil
+	}
+
+	options := &gitlab.ListBranchesOptions{
+		ListOptions: gitlab.ListOptions{PerPage: 100, Page: 1},
+	}
+	if prefix := strings.SplitN(protectedBranchName, "*", 2)[0]; prefix != "" {
+		options.Search = gitlab.String("^" + prefix)
+	}
+	for options.Page != 0 {
+		branches, resp, err := client.Branches.ListBranches(project, options, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, branch := range branches {
+			if protectedBranchNameMatches(protectedBranchName, branch.Name) {
+				matches = append(matches, branch.Name)
+			}
+		}
+
+		options.Page = resp.NextPage
+	}
+
+	return matches, nil
+}
+
+func getGitlabProtectedBranch(ctx context.Context, client *gitlab.Client, project string, branch string) (*gitlabProtectedBranch, error) {
+	// go-gitlab doesn't expose the IDs and deploy keys of the access levels
+	u := fmt.Sprintf("projects/%s/protected_branches/%s", gitlab.PathEscape(project), gitlab.PathEscape(branch))
+	return doGitlabProtectedBranchRequest(ctx, client, http.MethodGet, u, nil)
+}
+
+func updateGitlabProtectedBranch(ctx context.Context, client *gitlab.Client, project string, branch string, options *gitlabUpdateProtectedBranchOptions) (*gitlabProtectedBranch, error) {
+	u := fmt.Sprintf("projects/%s/protected_branches/%s", gitlab.PathEscape(project), gitlab.PathEscape(branch))
+	return doGitlabProtectedBranchRequest(ctx, client, http.MethodPatch, u, options)
+}
+
+func doGitlabProtectedBranchRequest(ctx context.Context, client *gitlab.Client, method string, u string, options interface{}) (*gitlabProtectedBranch, error) {
+	req, err := client.NewRequest(method, u, options, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+
+	pb := new(gitlabProtectedBranch)
+	if _, err := client.Do(req, pb); err != nil {
+		return nil, err
+	}
+
+	return pb, nil
+}
+
+func expandProtectRepositoryBranchesOptions(d *schema.ResourceData) *gitlab.ProtectRepositoryBranchesOptions {
+	allowedToPush := expandBranchPermissionOptions(d.Get("allowed_to_push").(*schema.Set).List())
+	allowedToMerge := expandBranchPermissionOptions(d.Get("allowed_to_merge").(*schema.Set).List())
+
+	options := &gitlab.ProtectRepositoryBranchesOptions{
+		Name:                      gitlab.String(d.Get("branch").(string)),
+		PushAccessLevel:           gitlab.AccessLevel(accessLevelID[d.Get("push_access_level").(string)]),
+		MergeAccessLevel:          gitlab.AccessLevel(accessLevelID[d.Get("merge_access_level").(string)]),
+		AllowForcePush:            gitlab.Bool(d.Get("allow_force_push").(bool)),
+		AllowedToPush:             &allowedToPush,
+		AllowedToMerge:            &allowedToMerge,
+		CodeOwnerApprovalRequired: gitlab.Bool(d.Get("code_owner_approval_required").(bool)),
+	}
+
+	if v, ok := d.GetOk("unprotect_access_level"); ok {
+		options.UnprotectAccessLevel = gitlab.AccessLevel(accessLevelID[v.(string)])
+	}
+
+	return options
+}
+
 func expandBranchPermissionOptions(allowedTo []interface{}) []*gitlab.BranchPermissionOptions {
 	result := make([]*gitlab.BranchPermissionOptions, 0)
 	for _, v := range allowedTo {
@@ -259,26 +613,119 @@ func expandBranchPermissionOptions(allowedTo []interface{}) []*gitlab.BranchPerm
 		if groupID, ok := v.(map[string]interface{})["group_id"]; ok && groupID != 0 {
 			opt.GroupID = gitlab.Int(groupID.(int))
 		}
+		if deployKeyID, ok := v.(map[string]interface{})["deploy_key_id"]; ok && deployKeyID != 0 {
+			opt.DeployKeyID = gitlab.Int(deployKeyID.(int))
+		}
 		result = append(result, opt)
 	}
 	return result
 }
 
+// expandBranchPermissionChanges computes the changes to turn the existing access levels of a protected branch
+// into the given role based access level and additional permissions.
+func expandBranchPermissionChanges(existing []*gitlabBranchAccessDescription, accessLevel *gitlab.AccessLevelValue, allowedTo []*gitlab.BranchPermissionOptions) []*gitlabBranchPermissionChange {
+	wanted := make(map[string]*gitlab.BranchPermissionOptions)
+	if accessLevel != nil {
+		wanted[branchPermissionKey(&gitlab.BranchPermissionOptions{AccessLevel: accessLevel})] = &gitlab.BranchPermissionOptions{AccessLevel: accessLevel}
+	}
+	for _, permission := range allowedTo {
+		wanted[branchPermissionKey(permission)] = permission
+	}
+
+	changes := make([]*gitlabBranchPermissionChange, 0)
+	for _, description := range existing {
+		key := branchAccessDescriptionKey(description)
+		if _, ok := wanted[key]; ok {
+			delete(wanted, key)
+			continue
+		}
+		changes = append(changes, &gitlabBranchPermissionChange{
+			ID:      gitlab.Int(description.ID),
+			Destroy: gitlab.Bool(true),
+		})
+	}
+
+	keys := make([]string, 0, len(wanted))
+	for key := range wanted {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		permission := wanted[key]
+		changes = append(changes, &gitlabBranchPermissionChange{
+			UserID:      permission.UserID,
+			GroupID:     permission.GroupID,
+			DeployKeyID: permission.DeployKeyID,
+			AccessLevel: permission.AccessLevel,
+		})
+	}
+
+	return changes
+}
+
+// roleBranchAccessDescriptions returns only the role based access levels, without the user, group and deploy key permissions.
+func roleBranchAccessDescriptions(descriptions []*gitlabBranchAccessDescription) []*gitlabBranchAccessDescription {
+	result := make([]*gitlabBranchAccessDescription, 0)
+	for _, description := range descriptions {
+		if description.UserID == 0 && description.GroupID == 0 && description.DeployKeyID == 0 {
+			result = append(result, description)
+		}
+	}
+	return result
+}
+
+func branchAccessDescriptionKey(description *gitlabBranchAccessDescription) string {
+	switch {
+	case description.UserID != 0:
+		return fmt.Sprintf("user:%d", description.UserID)
+	case description.GroupID != 0:
+		return fmt.Sprintf("group:%d", description.GroupID)
+	case description.DeployKeyID != 0:
+		return fmt.Sprintf("deploy_key:%d", description.DeployKeyID)
+	default:
+		return fmt.Sprintf("access_level:%d", description.AccessLevel)
+	}
+}
+
+func branchPermissionKey(permission *gitlab.BranchPermissionOptions) string {
+	switch {
+	case permission.UserID != nil:
+		return fmt.Sprintf("user:%d", *permission.UserID)
+	case permission.GroupID != nil:
+		return fmt.Sprintf("group:%d", *permission.GroupID)
+	case permission.DeployKeyID != nil:
+		return fmt.Sprintf("deploy_key:%d", *permission.DeployKeyID)
+	case permission.AccessLevel != nil:
+		return fmt.Sprintf("access_level:%d", *permission.AccessLevel)
+	default:
+		return ""
+	}
+}
+
 func schemaAllowedTo() *schema.Schema {
 	return &schema.Schema{
 		Description: "Defines permissions for action.",
 		Type:        schema.TypeSet,
 		Optional:    true,
-		ForceNew:    true,
 		Elem:        allowedToElem,
 	}
 }
 
-func convertAllowedAccessLevelsToBranchAccessDescriptions(descriptions []*gitlab.BranchAccessDescription) []stateBranchAccessDescription {
+func schemaAllowedToPush() *schema.Schema {
+	return &schema.Schema{
+		Description: "Defines permissions for action.",
+		Type:        schema.TypeSet,
+		Optional:    true,
+		Elem:        allowedToPushElem,
+	}
+}
+
+func convertAllowedAccessLevelsToBranchAccessDescriptions(descriptions []*gitlabBranchAccessDescription) []stateBranchAccessDescription {
 	result := make([]stateBranchAccessDescription, 0)
 
 	for _, description := range descriptions {
-		if description.UserID != 0 || description.GroupID != 0 {
+		if description.UserID != 0 || description.GroupID != 0 || description.DeployKeyID != 0 {
 			continue
 		}
 		result = append(result, stateBranchAccessDescription{
@@ -290,20 +737,51 @@ func convertAllowedAccessLevelsToBranchAccessDescriptions(descriptions []*gitlab
 	return result
 }
 
-func convertAllowedToToBranchAccessDescriptions(descriptions []*gitlab.BranchAccessDescription) []stateBranchAccessDescription {
-	result := make([]stateBranchAccessDescription, 0)
+// convertAllowedToToBranchAccessDescriptions converts the user, group and deploy key permissions of a protected branch to state.
+// The deploy key is only set if present, because only allowed_to_push supports it.
+func convertAllowedToToBranchAccessDescriptions(descriptions []*gitlabBranchAccessDescription) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0)
 
 	for _, description := range descriptions {
-		if description.UserID == 0 && description.GroupID == 0 {
+		if description.UserID == 0 && description.GroupID == 0 && description.DeployKeyID == 0 {
 			continue
 		}
-		result = append(result, stateBranchAccessDescription{
-			AccessLevel:            accessLevel[description.AccessLevel],
-			AccessLevelDescription: description.AccessLevelDescription,
-			UserID:                 description.UserID,
-			GroupID:                description.GroupID,
-		})
+		values := map[string]interface{}{
+			"access_level":             accessLevel[description.AccessLevel],
+			"access_level_description": description.AccessLevelDescription,
+			"user_id":                  description.UserID,
+			"group_id":                 description.GroupID,
+		}
+		if description.DeployKeyID != 0 {
+			values["deploy_key_id"] = description.DeployKeyID
+		}
+		result = append(result, values)
 	}
 
 	return result
 }
+
+// protectedBranchMatchesOptions reports whether the protected branch already has the settings of the given options.
+func protectedBranchMatchesOptions(pb *gitlabProtectedBranch, options *gitlab.ProtectRepositoryBranchesOptions) bool {
+	if options.CodeOwnerApprovalRequired != nil && pb.CodeOwnerApprovalRequired != *options.CodeOwnerApprovalRequired {
+		return false
+	}
+	if options.AllowForcePush != nil && pb.AllowForcePush != *options.AllowForcePush {
+		return false
+	}
+
+	var allowedToPush, allowedToMerge []*gitlab.BranchPermissionOptions
+	if options.AllowedToPush != nil {
+		allowedToPush = *options.AllowedToPush
+	}
+	if options.AllowedToMerge != nil {
+		allowedToMerge = *options.AllowedToMerge
+	}
+
+	if options.UnprotectAccessLevel != nil && len(expandBranchPermissionChanges(roleBranchAccessDescriptions(pb.UnprotectAccessLevels), options.UnprotectAccessLevel, nil)) > 0 {
+		return false
+	}
+
+	return len(expandBranchPermissionChanges(pb.PushAccessLevels, options.PushAccessLevel, allowedToPush)) == 0 &&
+		len(expandBranchPermissionChanges(pb.MergeAccessLevels, options.MergeAccessLevel, allowedToMerge)) == 0
+}
@@ -1,7 +1,9 @@
 package gitlab
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"regexp"
 	"strconv"
 	"testing"
@@ -229,6 +231,257 @@ func TestAccGitlabBranchProtection_createWithMultipleAccessLevels(t *testing.T)
 	})
 }
 
+func TestAccGitlabBranchProtection_adoptExisting(t *testing.T) {
+	var pb gitlab.ProtectedBranch
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabBranchProtectionDestroy,
+		Steps: []resource.TestStep{
+			// Adopt the protection GitLab creates for the default branch
+			{
+				Config: testAccGitlabBranchProtectionConfigAdoptExisting(rInt, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabBranchProtectionExists("gitlab_branch_protection.branch_protect", &pb),
+					testAccCheckGitlabBranchProtectionPersistsInStateCorrectly("gitlab_branch_protection.branch_protect", &pb),
+					testAccCheckGitlabBranchProtectionAttributes(&pb, &testAccGitlabBranchProtectionExpectedAttributes{
+						Name:             "main",
+						PushAccessLevel:  accessLevel[gitlab.NoPermissions],
+						MergeAccessLevel: accessLevel[gitlab.MaintainerPermissions],
+					}),
+				),
+			},
+			// Changing only adopt_existing doesn't touch the protection
+			{
+				Config: testAccGitlabBranchProtectionConfigAdoptExisting(rInt, false),
+				Check:  testAccCheckGitlabBranchProtectionComputedAttributes("gitlab_branch_protection.branch_protect", &pb),
+			},
+		},
+	})
+}
+
+func TestAccGitlabBranchProtection_adoptExistingFailure(t *testing.T) {
+	testAccCheck(t)
+
+	client := testAccNewClient(t)
+	testAccCheckEE(t, client)
+
+	project := testAccCreateProject(t, client)
+	existing := testAccCreateProtectedBranches(t, client, project, 1)[0]
+	// Protecting a branch for a user who isn't a member of the project fails.
+	user := testAccCreateUsers(t, client, 1)[0]
+
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "gitlab_branch_protection" "branch_protect" {
+  project            = %d
+  branch             = %q
+  push_access_level  = "no one"
+  merge_access_level = "developer"
+  adopt_existing     = true
+
+  allowed_to_push {
+    user_id = %d
+  }
+}
+				`, project.ID, existing.Name, user.ID),
+				ExpectError: regexp.MustCompile(`error (updating existing protected|protecting) branch`),
+			},
+			// The existing protection is still in place
+			{
+				Config: fmt.Sprintf(`
+data "gitlab_project" "foo" {
+  id = %d
+}
+				`, project.ID),
+				Check: func(*terraform.State) error {
+					pb, _, err := client.ProtectedBranches.GetProtectedBranch(project.ID, existing.Name)
+					if err != nil {
+						return fmt.Errorf("the existing protection of branch %q is gone: %v", existing.Name, err)
+					}
+					if len(pb.MergeAccessLevels) != 1 || pb.MergeAccessLevels[0].AccessLevel != gitlab.MaintainerPermissions {
+						return fmt.Errorf("the existing protection of branch %q has been changed: %+v", existing.Name, pb)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccGitlabBranchProtection_forcePushAndDeployKey(t *testing.T) {
+	var pb gitlab.ProtectedBranch
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabBranchProtectionDestroy,
+		Steps: []resource.TestStep{
+			// Create a Branch Protection which allows force pushes and a deploy key to push
+			{
+				SkipFunc: isRunningInCE,
+				Config:   testAccGitlabBranchProtectionConfigForcePushAndDeployKey(rInt, true, "developer"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabBranchProtectionExists("gitlab_branch_protection.branch_protect", &pb),
+					resource.TestCheckResourceAttr("gitlab_branch_protection.branch_protect", "allow_force_push", "true"),
+					resource.TestCheckResourceAttr("gitlab_branch_protection.branch_protect", "unprotect_access_level", "developer"),
+					resource.TestCheckResourceAttr("gitlab_branch_protection.branch_protect", "allowed_to_push.#", "1"),
+					resource.TestCheckTypeSetElemAttrPair("gitlab_branch_protection.branch_protect", "allowed_to_push.*.deploy_key_id", "gitlab_deploy_key.foo", "id"),
+				),
+			},
+			// Update the Branch Protection in place
+			{
+				SkipFunc: isRunningInCE,
+				Config:   testAccGitlabBranchProtectionConfigForcePushAndDeployKey(rInt, false, "maintainer"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabBranchProtectionExists("gitlab_branch_protection.branch_protect", &pb),
+					resource.TestCheckResourceAttr("gitlab_branch_protection.branch_protect", "allow_force_push", "false"),
+					resource.TestCheckResourceAttr("gitlab_branch_protection.branch_protect", "unprotect_access_level", "maintainer"),
+					resource.TestCheckResourceAttr("gitlab_branch_protection.branch_protect", "allowed_to_push.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccGitlabBranchProtection_wildcard(t *testing.T) {
+	var pb gitlab.ProtectedBranch
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabBranchProtectionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGitlabBranchProtectionConfigWildcard(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabBranchProtectionExists("gitlab_branch_protection.branch_protect", &pb),
+					resource.TestCheckResourceAttr("gitlab_branch_protection.branch_protect", "branch", "release/*"),
+					resource.TestCheckResourceAttr("gitlab_branch_protection.branch_protect", "matching_branches.#", "2"),
+					resource.TestCheckResourceAttr("gitlab_branch_protection.branch_protect", "matching_branches.0", "release/1.0"),
+					resource.TestCheckResourceAttr("gitlab_branch_protection.branch_protect", "matching_branches.1", "release/2.0"),
+				),
+			},
+			{
+				Config:      testAccGitlabBranchProtectionConfigInvalidName(rInt),
+				ExpectError: regexp.MustCompile(`is not a valid branch name or wildcard`),
+			},
+		},
+	})
+}
+
+func TestGitlabBranchProtection_protectedBranchNameMatches(t *testing.T) {
+	cases := []struct {
+		protectedBranchName string
+		branch              string
+		want                bool
+	}{
+		{"main", "main", true},
+		{"main", "main2", false},
+		{"release/*", "release/1.0", true},
+		{"release/*", "release/1.0/hotfix", true},
+		{"release/*", "release", false},
+		{"*-stable", "14-0-stable", true},
+		{"*-stable", "14-0-stable-ee", false},
+		{"*", "any/branch", true},
+		{"feature.*", "feature-1", false},
+		{"Release/*", "release/1.0", false},
+	}
+
+	for _, tc := range cases {
+		if got := protectedBranchNameMatches(tc.protectedBranchName, tc.branch); got != tc.want {
+			t.Errorf("protectedBranchNameMatches(%q, %q) = %t; want %t", tc.protectedBranchName, tc.branch, got, tc.want)
+		}
+	}
+}
+
+func TestGitlabBranchProtection_validateProtectedBranchName(t *testing.T) {
+	cases := map[string]bool{
+		"main":          true,
+		"release/*":     true,
+		"*-stable":      true,
+		"feature/**":    true,
+		"":              false,
+		"release/ 1":    false,
+		"release/[0-9]": false,
+		"release/?":     false,
+		"release..1":    false,
+		"release//1":    false,
+		"/release":      false,
+		"release/":      false,
+		"release.lock":  false,
+		"-release":      false,
+	}
+
+	for name, valid := range cases {
+		_, errs := validateProtectedBranchNameFunc(name, "branch")
+		if valid && len(errs) > 0 {
+			t.Errorf("expected %q to be valid, got %v", name, errs)
+		}
+		if !valid && len(errs) == 0 {
+			t.Errorf("expected %q to be invalid", name)
+		}
+	}
+}
+
+func TestGitlabBranchProtection_expandBranchPermissionChanges(t *testing.T) {
+	existing := []*gitlabBranchAccessDescription{
+		{ID: 1, BranchAccessDescription: gitlab.BranchAccessDescription{AccessLevel: gitlab.MaintainerPermissions}},
+		{ID: 2, BranchAccessDescription: gitlab.BranchAccessDescription{AccessLevel: gitlab.DeveloperPermissions, UserID: 42}},
+		{ID: 3, BranchAccessDescription: gitlab.BranchAccessDescription{AccessLevel: gitlab.DeveloperPermissions, GroupID: 7}},
+	}
+
+	cases := []struct {
+		name        string
+		accessLevel *gitlab.AccessLevelValue
+		allowedTo   []*gitlab.BranchPermissionOptions
+		want        []*gitlabBranchPermissionChange
+	}{
+		{
+			name:        "same settings in different order",
+			accessLevel: gitlab.AccessLevel(gitlab.MaintainerPermissions),
+			allowedTo:   []*gitlab.BranchPermissionOptions{{GroupID: gitlab.Int(7)}, {UserID: gitlab.Int(42)}},
+			want:        []*gitlabBranchPermissionChange{},
+		},
+		{
+			name:        "different access level",
+			accessLevel: gitlab.AccessLevel(gitlab.DeveloperPermissions),
+			allowedTo:   []*gitlab.BranchPermissionOptions{{GroupID: gitlab.Int(7)}, {UserID: gitlab.Int(42)}},
+			want: []*gitlabBranchPermissionChange{
+				{ID: gitlab.Int(1), Destroy: gitlab.Bool(true)},
+				{AccessLevel: gitlab.AccessLevel(gitlab.DeveloperPermissions)},
+			},
+		},
+		{
+			name:        "user replaced by deploy key",
+			accessLevel: gitlab.AccessLevel(gitlab.MaintainerPermissions),
+			allowedTo:   []*gitlab.BranchPermissionOptions{{GroupID: gitlab.Int(7)}, {DeployKeyID: gitlab.Int(5)}},
+			want: []*gitlabBranchPermissionChange{
+				{ID: gitlab.Int(2), Destroy: gitlab.Bool(true)},
+				{DeployKeyID: gitlab.Int(5)},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := expandBranchPermissionChanges(existing, tc.accessLevel, tc.allowedTo)
+			if !reflect.DeepEqual(got, tc.want) {
+				gotJSON, _ := json.Marshal(got)
+				wantJSON, _ := json.Marshal(tc.want)
+				t.Errorf("got %s; want %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
+
 func testAccCheckGitlabBranchProtectionPersistsInStateCorrectly(n string, pb *gitlab.ProtectedBranch) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -698,3 +951,120 @@ resource "gitlab_branch_protection" "branch_protect" {
 }
 	`, rInt)
 }
+
+func testAccGitlabBranchProtectionConfigAdoptExisting(rInt int, adoptExisting bool) string {
+	return fmt.Sprintf(`
+resource "gitlab_project" "foo" {
+  name = "foo-%[1]d"
+  description = "Terraform acceptance tests"
+
+  initialize_with_readme = true
+  default_branch         = "main"
+
+  # So that acceptance tests can be run in a gitlab organization
+  # with no billing
+  visibility_level = "public"
+}
+
+resource "gitlab_branch_protection" "branch_protect" {
+  project            = gitlab_project.foo.id
+  branch             = gitlab_project.foo.default_branch
+  push_access_level  = "no one"
+  merge_access_level = "maintainer"
+  adopt_existing     = %[2]t
+}
+	`, rInt, adoptExisting)
+}
+
+func testAccGitlabBranchProtectionConfigForcePushAndDeployKey(rInt int, allowForcePush bool, unprotectAccessLevel string) string {
+	return fmt.Sprintf(`
+resource "gitlab_project" "foo" {
+  name = "foo-%[1]d"
+  description = "Terraform acceptance tests"
+
+  # So that acceptance tests can be run in a gitlab organization
+  # with no billing
+  visibility_level = "public"
+}
+
+resource "gitlab_deploy_key" "foo" {
+  project  = gitlab_project.foo.id
+  title    = "deployKey-%[1]d"
+  key      = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQCj13ozEBZ0s4el4k6mYqoyIKKKMh9hHY0sAYqSPXs2zGuVFZss1P8TPuwmdXVjHR7TiRXwC49zDrkyWJgiufggYJ1VilOohcMOODwZEJz+E5q4GCfHuh90UEh0nl8B2R0Uoy0LPeg93uZzy0hlHApsxRf/XZJz/1ytkZvCtxdllxfImCVxJReMeRVEqFCTCvy3YuJn0bce7ulcTFRvtgWOpQsr6GDK8YkcCCv2eZthVlrEwy6DEpAKTRiRLGgUj4dPO0MmO4cE2qD4ualY01PhNORJ8Q++I+EtkGt/VALkecwFuBkl18/gy+yxNJHpKc/8WVVinDeFrd/HhiY9yU0d"
+  can_push = true
+}
+
+resource "gitlab_branch_protection" "branch_protect" {
+  project                = gitlab_project.foo.id
+  branch                 = "BranchProtect-%[1]d"
+  push_access_level      = "maintainer"
+  merge_access_level     = "developer"
+  unprotect_access_level = "%[3]s"
+  allow_force_push       = %[2]t
+
+  allowed_to_push {
+    deploy_key_id = gitlab_deploy_key.foo.id
+  }
+}
+	`, rInt, allowForcePush, unprotectAccessLevel)
+}
+
+func testAccGitlabBranchProtectionConfigWildcard(rInt int) string {
+	return fmt.Sprintf(`
+resource "gitlab_project" "foo" {
+  name = "foo-%[1]d"
+  description = "Terraform acceptance tests"
+
+  initialize_with_readme = true
+  default_branch         = "main"
+
+  # So that acceptance tests can be run in a gitlab organization
+  # with no billing
+  visibility_level = "public"
+}
+
+resource "gitlab_branch" "release_1" {
+  project = gitlab_project.foo.id
+  name    = "release/1.0"
+  ref     = "main"
+}
+
+resource "gitlab_branch" "release_2" {
+  project = gitlab_project.foo.id
+  name    = "release/2.0"
+  ref     = "main"
+}
+
+resource "gitlab_branch_protection" "branch_protect" {
+  project            = gitlab_project.foo.id
+  branch             = "release/*"
+  push_access_level  = "maintainer"
+  merge_access_level = "developer"
+
+  depends_on = [gitlab_branch.release_1, gitlab_branch.release_2]
+}
+	`, rInt)
+}
+
+func testAccGitlabBranchProtectionConfigInvalidName(rInt int) string {
+	return fmt.Sprintf(`
+resource "gitlab_project" "foo" {
+  name = "foo-%[1]d"
+  description = "Terraform acceptance tests"
+
+  initialize_with_readme = true
+  default_branch         = "main"
+
+  # So that acceptance tests can be run in a gitlab organization
+  # with no billing
+  visibility_level = "public"
+}
+
+resource "gitlab_branch_protection" "branch_protect" {
+  project            = gitlab_project.foo.id
+  branch             = "release/[0-9]*"
+  push_access_level  = "maintainer"
+  merge_access_level = "developer"
+}
+	`, rInt)
+}
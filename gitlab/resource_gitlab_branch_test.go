@@ -0,0 +1,87 @@
+package gitlab
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func TestAccGitlabBranch_basic(t *testing.T) {
+	testAccCheck(t)
+
+	client := testAccNewClient(t)
+	project := testAccCreateProject(t, client)
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabBranchDestroy(client, project, "feature"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGitlabBranchConfig(project, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlab_branch.this", "name", "feature"),
+					resource.TestCheckResourceAttr("gitlab_branch.this", "protected", "false"),
+					resource.TestCheckResourceAttr("gitlab_branch.this", "default", "false"),
+					resource.TestCheckResourceAttrSet("gitlab_branch.this", "commit_sha"),
+					resource.TestCheckResourceAttrSet("gitlab_branch.this", "web_url"),
+				),
+			},
+			{
+				ResourceName:            "gitlab_branch.this",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"ref", "keep_on_destroy"},
+			},
+		},
+	})
+}
+
+func TestAccGitlabBranch_keepOnDestroy(t *testing.T) {
+	testAccCheck(t)
+
+	client := testAccNewClient(t)
+	project := testAccCreateProject(t, client)
+
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		CheckDestroy: func(s *terraform.State) error {
+			if _, _, err := client.Branches.GetBranch(project.ID, "feature"); err != nil {
+				return fmt.Errorf("branch was not kept on destroy: %v", err)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGitlabBranchConfig(project, true),
+				Check:  resource.TestCheckResourceAttr("gitlab_branch.this", "keep_on_destroy", "true"),
+			},
+		},
+	})
+}
+
+func testAccCheckGitlabBranchDestroy(client *gitlab.Client, project *gitlab.Project, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		_, _, err := client.Branches.GetBranch(project.ID, name)
+		if err == nil {
+			return fmt.Errorf("branch %q still exists", name)
+		}
+		if !is404(err) {
+			return err
+		}
+		return nil
+	}
+}
+
+func testAccGitlabBranchConfig(project *gitlab.Project, keepOnDestroy bool) string {
+	return fmt.Sprintf(`
+resource "gitlab_branch" "this" {
+  project         = %d
+  name            = "feature"
+  ref             = %q
+  keep_on_destroy = %t
+}
+	`, project.ID, project.DefaultBranch, keepOnDestroy)
+}
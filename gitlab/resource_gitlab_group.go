@@ -27,6 +27,7 @@ func resourceGitlabGroup() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		CustomizeDiff: customizeDiffAvatarHash,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -148,6 +149,21 @@ func resourceGitlabGroup() *schema.Resource {
 				Computed:    true,
 				Sensitive:   true,
 			},
+			"avatar": {
+				Description: "A local path to the avatar image to upload. **Note**: not available for imported resources. Remove it to delete the avatar of the group.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"avatar_hash": {
+				Description: "The SHA256 hash of the local avatar image, which is used to detect changes of the image.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"avatar_url": {
+				Description: "The URL of the avatar image of the group.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -221,6 +237,14 @@ func resourceGitlabGroupCreate(ctx context.Context, d *schema.ResourceData, meta
 
 	d.SetId(fmt.Sprintf("%d", group.ID))
 
+	// go-gitlab doesn't support uploading group avatars
+	if v, ok := d.GetOk("avatar"); ok {
+		log.Printf("[DEBUG] upload avatar %q for group %s", v.(string), d.Id())
+		if err := updateGitlabAvatar(ctx, client, fmt.Sprintf("groups/%s", d.Id()), v.(string)); err != nil {
+			return diag.Errorf("failed to upload avatar for group %s: %s", d.Id(), err)
+		}
+	}
+
 	return resourceGitlabGroupRead(ctx, d, meta)
 }
 
@@ -264,6 +288,7 @@ func resourceGitlabGroupRead(ctx context.Context, d *schema.ResourceData, meta i
 	d.Set("runners_token", group.RunnersToken)
 	d.Set("share_with_group_lock", group.ShareWithGroupLock)
 	d.Set("default_branch_protection", group.DefaultBranchProtection)
+	d.Set("avatar_url", group.AvatarURL)
 
 	return nil
 }
@@ -342,6 +367,13 @@ func resourceGitlabGroupUpdate(ctx context.Context, d *schema.ResourceData, meta
 		return diag.FromErr(err)
 	}
 
+	if d.HasChanges("avatar", "avatar_hash") {
+		log.Printf("[DEBUG] update avatar of group %s", d.Id())
+		if err := updateGitlabAvatar(ctx, client, fmt.Sprintf("groups/%s", d.Id()), d.Get("avatar").(string)); err != nil {
+			return diag.Errorf("failed to update avatar of group %s: %s", d.Id(), err)
+		}
+	}
+
 	return resourceGitlabGroupRead(ctx, d, meta)
 }
 
@@ -0,0 +1,173 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func resourceGitlabGroupBranchProtection() *schema.Resource {
+	resourceSchema := schemaBranchProtectionSettings()
+	resourceSchema["group"] = &schema.Schema{
+		Description: "The ID or full path of the top-level group.",
+		Type:        schema.TypeString,
+		Required:    true,
+		ForceNew:    true,
+	}
+
+	return &schema.Resource{
+		Description: "This resource allows you to protect a branch or wildcard for all projects of a group, with the same settings as `gitlab_branch_protection`.\n\n" +
+			"-> Group level protected branches require GitLab Premium 15.9 or later and can only be defined for top-level groups. " +
+			"Please refer to [Gitlab API documentation](https://docs.gitlab.com/ee/api/group_protected_branches.html) for further information.",
+
+		CreateContext: resourceGitlabGroupBranchProtectionCreate,
+		ReadContext:   resourceGitlabGroupBranchProtectionRead,
+		UpdateContext: resourceGitlabGroupBranchProtectionUpdate,
+		DeleteContext: resourceGitlabGroupBranchProtectionDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: resourceSchema,
+	}
+}
+
+func resourceGitlabGroupBranchProtectionCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	group := d.Get("group").(string)
+	branch := d.Get("branch").(string)
+
+	if err := checkGroupBranchProtectionSupported(ctx, client); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] create gitlab group branch protection on branch %q for group %s", branch, group)
+
+	options := expandProtectRepositoryBranchesOptions(d)
+
+	u := fmt.Sprintf("groups/%s/protected_branches", gitlab.PathEscape(group))
+	pb, err := doGitlabProtectedBranchRequest(ctx, client, http.MethodPost, u, options)
+	if err != nil {
+		return diag.Errorf("error protecting branch %q on group %q: %v", branch, group, err)
+	}
+
+	if !pb.CodeOwnerApprovalRequired && *options.CodeOwnerApprovalRequired {
+		return diag.Errorf("feature unavailable: code owner approvals")
+	}
+
+	d.SetId(buildTwoPartID(&group, &pb.Name))
+
+	return resourceGitlabGroupBranchProtectionRead(ctx, d, meta)
+}
+
+func resourceGitlabGroupBranchProtectionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	group, branch, err := parseTwoPartID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] read gitlab group branch protection for group %s, branch %s", group, branch)
+
+	pb, err := getGitlabGroupProtectedBranch(ctx, client, group, branch)
+	if err != nil {
+		if is404(err) {
+			log.Printf("[DEBUG] gitlab group branch protection for group %s, branch %s not found, removing from state", group, branch)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	d.Set("group", group)
+	d.Set("branch", pb.Name)
+
+	if err := setProtectedBranchToState(d, pb); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("branch_protection_id", pb.ID)
+
+	return nil
+}
+
+func resourceGitlabGroupBranchProtectionUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	group := d.Get("group").(string)
+	branch := d.Get("branch").(string)
+
+	log.Printf("[DEBUG] update gitlab group branch protection for group %s, branch %s", group, branch)
+
+	pb, err := getGitlabGroupProtectedBranch(ctx, client, group, branch)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	options := expandUpdateProtectedBranchOptions(d, pb, d.HasChanges)
+	if options.isEmpty() {
+		return resourceGitlabGroupBranchProtectionRead(ctx, d, meta)
+	}
+
+	u := fmt.Sprintf("groups/%s/protected_branches/%s", gitlab.PathEscape(group), gitlab.PathEscape(branch))
+	updated, err := doGitlabProtectedBranchRequest(ctx, client, http.MethodPatch, u, options)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if !updated.CodeOwnerApprovalRequired && d.Get("code_owner_approval_required").(bool) {
+		return diag.Errorf("feature unavailable: code owner approvals")
+	}
+
+	return resourceGitlabGroupBranchProtectionRead(ctx, d, meta)
+}
+
+func resourceGitlabGroupBranchProtectionDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	group := d.Get("group").(string)
+	branch := d.Get("branch").(string)
+
+	log.Printf("[DEBUG] delete gitlab group branch protection for group %s, branch %s", group, branch)
+
+	u := fmt.Sprintf("groups/%s/protected_branches/%s", gitlab.PathEscape(group), gitlab.PathEscape(branch))
+	req, err := client.NewRequest(http.MethodDelete, u, nil, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if _, err := client.Do(req, nil); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func getGitlabGroupProtectedBranch(ctx context.Context, client *gitlab.Client, group string, branch string) (*gitlabProtectedBranch, error) {
+	// go-gitlab doesn't support group level protected branches yet
+	u := fmt.Sprintf("groups/%s/protected_branches/%s", gitlab.PathEscape(group), gitlab.PathEscape(branch))
+	return doGitlabProtectedBranchRequest(ctx, client, http.MethodGet, u, nil)
+}
+
+// checkGroupBranchProtectionSupported returns an error if the GitLab instance doesn't support group level protected branches.
+func checkGroupBranchProtectionSupported(ctx context.Context, client *gitlab.Client) error {
+	version, err := getGitlabVersion(ctx, client)
+	if err != nil {
+		return err
+	}
+	if version.isCommunityEdition() {
+		return fmt.Errorf("group level protected branches require GitLab Enterprise Edition")
+	}
+
+	isSupported, err := version.isAtLeast("15.9")
+	if err != nil {
+		return err
+	}
+	if !isSupported {
+		return fmt.Errorf("group level protected branches require GitLab 15.9 or later")
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/xanzy/go-gitlab"
+)
+
+func TestAccGitlabGroupBranchProtection_basic(t *testing.T) {
+	testAccCheck(t)
+
+	client := testAccNewClient(t)
+	testAccCheckEE(t, client)
+
+	if isSupported, err := isGitLabVersionAtLeast(client, "15.9")(); err != nil {
+		t.Fatalf("could not check GitLab version: %v", err)
+	} else if !isSupported {
+		t.Skip("Group level protected branches require GitLab 15.9 or later")
+	}
+
+	group := testAccCreateGroups(t, client, 1)[0]
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabGroupBranchProtectionDestroy(client),
+		Steps: []resource.TestStep{
+			// Protect a wildcard for the group
+			{
+				Config: fmt.Sprintf(`
+resource "gitlab_group_branch_protection" "this" {
+  group              = "%d"
+  branch             = "release/*"
+  push_access_level  = "maintainer"
+  merge_access_level = "developer"
+}
+`, group.ID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlab_group_branch_protection.this", "push_access_level", "maintainer"),
+					resource.TestCheckResourceAttr("gitlab_group_branch_protection.this", "merge_access_level", "developer"),
+					resource.TestCheckResourceAttr("gitlab_group_branch_protection.this", "allow_force_push", "false"),
+					resource.TestCheckResourceAttrSet("gitlab_group_branch_protection.this", "branch_protection_id"),
+				),
+			},
+			// Verify import
+			{
+				ResourceName:      "gitlab_group_branch_protection.this",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update the protection in place
+			{
+				Config: fmt.Sprintf(`
+resource "gitlab_group_branch_protection" "this" {
+  group              = "%d"
+  branch             = "release/*"
+  push_access_level  = "no one"
+  merge_access_level = "maintainer"
+  allow_force_push   = true
+}
+`, group.ID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlab_group_branch_protection.this", "push_access_level", "no one"),
+					resource.TestCheckResourceAttr("gitlab_group_branch_protection.this", "merge_access_level", "maintainer"),
+					resource.TestCheckResourceAttr("gitlab_group_branch_protection.this", "allow_force_push", "true"),
+				),
+			},
+			// Verify import
+			{
+				ResourceName:      "gitlab_group_branch_protection.this",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckGitlabGroupBranchProtectionDestroy(client *gitlab.Client) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != "gitlab_group_branch_protection" {
+				continue
+			}
+
+			group, branch, err := parseTwoPartID(rs.Primary.ID)
+			if err != nil {
+				return err
+			}
+
+			_, err = getGitlabGroupProtectedBranch(context.Background(), client, group, branch)
+			if err == nil {
+				return fmt.Errorf("group branch protection %s still exists", rs.Primary.ID)
+			}
+			if !is404(err) {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,151 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+var groupProtectedEnvironmentTiers = []string{"production", "staging", "testing", "development", "other"}
+
+func resourceGitlabGroupProtectedEnvironment() *schema.Resource {
+	resourceSchema := schemaProtectedEnvironmentSettings()
+	resourceSchema["group"] = &schema.Schema{
+		Description:  "The ID or full path of the group.",
+		Type:         schema.TypeString,
+		Required:     true,
+		ForceNew:     true,
+		ValidateFunc: validation.StringIsNotEmpty,
+	}
+	resourceSchema["environment"] = &schema.Schema{
+		Description:  "The deployment tier of the environments to protect. Valid values are `production`, `staging`, `testing`, `development` and `other`.",
+		Type:         schema.TypeString,
+		Required:     true,
+		ForceNew:     true,
+		ValidateFunc: validation.StringInSlice(groupProtectedEnvironmentTiers, false),
+	}
+
+	return &schema.Resource{
+		Description: "This resource allows you to protect all environments of a deployment tier in the projects of a group.\n\n" +
+			"-> Group level protected environments require GitLab Premium 14.0 or later. " +
+			"Please refer to [Gitlab API documentation](https://docs.gitlab.com/ee/api/group_protected_environments.html) for further information.",
+
+		CreateContext: resourceGitlabGroupProtectedEnvironmentCreate,
+		ReadContext:   resourceGitlabGroupProtectedEnvironmentRead,
+		UpdateContext: resourceGitlabGroupProtectedEnvironmentUpdate,
+		DeleteContext: resourceGitlabGroupProtectedEnvironmentDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: customizeDiffProtectedEnvironmentAccess,
+		Schema:        resourceSchema,
+	}
+}
+
+func resourceGitlabGroupProtectedEnvironmentCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	group := d.Get("group").(string)
+	environment := d.Get("environment").(string)
+
+	if err := checkGroupProtectedEnvironmentSupported(ctx, client); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] create gitlab protected environment %q for group %s", environment, group)
+
+	pe, err := createGitlabProtectedEnvironment(ctx, client, fmt.Sprintf("groups/%s", gitlab.PathEscape(group)), d)
+	if err != nil {
+		return diag.Errorf("error protecting environment %q on group %q: %v", environment, group, err)
+	}
+
+	d.SetId(buildTwoPartID(&group, &pe.Name))
+
+	if err := checkProtectedEnvironmentFeatures(d, pe); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceGitlabGroupProtectedEnvironmentRead(ctx, d, meta)
+}
+
+func resourceGitlabGroupProtectedEnvironmentRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	group, environment, err := parseTwoPartID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] read gitlab protected environment %q for group %s", environment, group)
+
+	pe, err := getGitlabProtectedEnvironment(ctx, client, fmt.Sprintf("groups/%s", gitlab.PathEscape(group)), environment)
+	if err != nil {
+		if is404(err) {
+			log.Printf("[DEBUG] gitlab protected environment %q for group %s not found, removing from state", environment, group)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	d.Set("group", group)
+	d.Set("environment", pe.Name)
+
+	if err := setProtectedEnvironmentToState(d, pe); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceGitlabGroupProtectedEnvironmentUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	group := d.Get("group").(string)
+	environment := d.Get("environment").(string)
+
+	log.Printf("[DEBUG] update gitlab protected environment %q for group %s", environment, group)
+
+	if err := updateGitlabProtectedEnvironment(ctx, client, fmt.Sprintf("groups/%s", gitlab.PathEscape(group)), d); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceGitlabGroupProtectedEnvironmentRead(ctx, d, meta)
+}
+
+func resourceGitlabGroupProtectedEnvironmentDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	group := d.Get("group").(string)
+	environment := d.Get("environment").(string)
+
+	log.Printf("[DEBUG] delete gitlab protected environment %q for group %s", environment, group)
+
+	if err := deleteGitlabProtectedEnvironment(ctx, client, fmt.Sprintf("groups/%s", gitlab.PathEscape(group)), environment); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// checkGroupProtectedEnvironmentSupported returns an error if the GitLab instance doesn't support group level protected environments.
+func checkGroupProtectedEnvironmentSupported(ctx context.Context, client *gitlab.Client) error {
+	version, err := getGitlabVersion(ctx, client)
+	if err != nil {
+		return err
+	}
+	if version.isCommunityEdition() {
+		return fmt.Errorf("group level protected environments require GitLab Enterprise Edition")
+	}
+
+	isSupported, err := version.isAtLeast("14.0")
+	if err != nil {
+		return err
+	}
+	if !isSupported {
+		return fmt.Errorf("group level protected environments require GitLab 14.0 or later")
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package gitlab
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccGitlabGroupProtectedEnvironment_basic(t *testing.T) {
+	testAccCheck(t)
+
+	client := testAccNewClient(t)
+	testAccCheckEE(t, client)
+
+	group := testAccCreateGroups(t, client, 1)[0]
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabProtectedEnvironmentDestroy(client, "gitlab_group_protected_environment", "groups"),
+		Steps: []resource.TestStep{
+			// Protect the production tier for maintainers
+			{
+				Config: fmt.Sprintf(`
+resource "gitlab_group_protected_environment" "this" {
+  group       = %d
+  environment = "production"
+
+  deploy_access_levels {
+    access_level = "maintainer"
+  }
+}
+`, group.ID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlab_group_protected_environment.this", "deploy_access_levels.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs("gitlab_group_protected_environment.this", "deploy_access_levels.*", map[string]string{
+						"access_level": "maintainer",
+					}),
+				),
+			},
+			// Verify import
+			{
+				ResourceName:      "gitlab_group_protected_environment.this",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Allow developers to deploy instead
+			{
+				Config: fmt.Sprintf(`
+resource "gitlab_group_protected_environment" "this" {
+  group       = %d
+  environment = "production"
+
+  deploy_access_levels {
+    access_level = "developer"
+  }
+}
+`, group.ID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlab_group_protected_environment.this", "deploy_access_levels.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs("gitlab_group_protected_environment.this", "deploy_access_levels.*", map[string]string{
+						"access_level": "developer",
+					}),
+				),
+			},
+			// Verify import
+			{
+				ResourceName:      "gitlab_group_protected_environment.this",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
@@ -3,6 +3,8 @@ package gitlab
 import (
 	"fmt"
 	"net/http"
+	"path/filepath"
+	"regexp"
 	"testing"
 	"time"
 
@@ -106,6 +108,43 @@ func TestAccGitlabGroup_import(t *testing.T) {
 	})
 }
 
+func TestAccGitlabGroup_avatar(t *testing.T) {
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGitlabGroupConfigAvatar(rInt, "avatar.png"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("gitlab_group.foo", "avatar_hash"),
+					resource.TestMatchResourceAttr("gitlab_group.foo", "avatar_url", regexp.MustCompile(`avatar\.png$`)),
+				),
+			},
+			{
+				Config: testAccGitlabGroupConfigAvatar(rInt, "avatar-update.png"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("gitlab_group.foo", "avatar_url", regexp.MustCompile(`avatar-update\.png$`)),
+				),
+			},
+			{
+				ResourceName:            "gitlab_group.foo",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"avatar", "avatar_hash"},
+			},
+			{
+				Config: testAccGitlabGroupConfig(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlab_group.foo", "avatar_url", ""),
+				),
+			},
+		},
+	})
+}
+
 func TestAccGitlabGroup_nested(t *testing.T) {
 	var group gitlab.Group
 	var group2 gitlab.Group
@@ -525,3 +564,18 @@ resource "gitlab_group" "nested_foo" {
 }
   `, rInt, rInt, rInt, rInt, rInt, rInt)
 }
+
+func testAccGitlabGroupConfigAvatar(rInt int, avatar string) string {
+	return fmt.Sprintf(`
+resource "gitlab_group" "foo" {
+  name        = "foo-name-%d"
+  path        = "foo-path-%d"
+  description = "Terraform acceptance tests"
+  avatar      = %q
+
+  # So that acceptance tests can be run in a gitlab organization
+  # with no billing
+  visibility_level = "public"
+}
+  `, rInt, rInt, filepath.Join("testdata", avatar))
+}
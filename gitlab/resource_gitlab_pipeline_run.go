@@ -0,0 +1,331 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+// pipelineRunFinishedStatuses are the statuses of pipelines which don't change anymore by themselves.
+// All other statuses, including the ones of future GitLab versions, are waited for,
+// except for the manual status of a pipeline blocked by a manual job, which depends on wait_for_manual.
+var pipelineRunFinishedStatuses = map[string]bool{
+	"success":  true,
+	"failed":   true,
+	"canceled": true,
+	"skipped":  true,
+}
+
+func resourceGitlabPipelineRun() *schema.Resource {
+	return &schema.Resource{
+		Description: "This resource allows you to run a pipeline for a ref, e.g. to bootstrap newly provisioned infrastructure.\n\n" +
+			"The pipeline is created with the [pipelines API](https://docs.gitlab.com/ee/api/pipelines.html#create-a-new-pipeline), " +
+			"or with the [pipeline triggers API](https://docs.gitlab.com/ee/ci/triggers/) if a `trigger_token` is given. " +
+			"A new pipeline is run whenever the arguments or the `triggers` change. " +
+			"Destroying the resource only removes it from the state, the pipeline is kept.",
+
+		CreateContext: resourceGitlabPipelineRunCreate,
+		ReadContext:   resourceGitlabPipelineRunRead,
+		UpdateContext: resourceGitlabPipelineRunUpdate,
+		DeleteContext: resourceGitlabPipelineRunDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Description:  "The ID or full path of the project to run the pipeline in.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"ref": {
+				Description:  "The branch or tag to run the pipeline for.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"variables": {
+				Description: "Variables to pass to the pipeline.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"trigger_token": {
+				Description: "A pipeline trigger token, e.g. of `gitlab_pipeline_trigger`, to run the pipeline with instead of the token of the provider.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Sensitive:   true,
+			},
+			"triggers": {
+				Description: "Arbitrary map of values that, when changed, will run a new pipeline.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"wait_for_completion": {
+				Description: "Wait until the pipeline has finished, at most for the create timeout. The apply fails if the pipeline doesn't succeed. A pipeline which is blocked by a manual job, i.e. has the `manual` status, counts as succeeded, unless `wait_for_manual` is set.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"wait_for_manual": {
+				Description: "With `wait_for_completion`, keep waiting while the pipeline is blocked by a manual job, until the job is run and the pipeline has finished.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"pipeline_id": {
+				Description: "The ID of the pipeline.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"sha": {
+				Description: "The SHA of the commit the pipeline runs for.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"status": {
+				Description: "The status of the pipeline.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"web_url": {
+				Description: "The URL to visit the pipeline.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"jobs": {
+				Description: "The jobs of the pipeline.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Description: "The ID of the job.",
+							Type:        schema.TypeInt,
+							Computed:    true,
+						},
+						"name": {
+							Description: "The name of the job.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"stage": {
+							Description: "The stage of the job.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"status": {
+							Description: "The status of the job.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"web_url": {
+							Description: "The URL to visit the job.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceGitlabPipelineRunCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	project := d.Get("project").(string)
+	ref := d.Get("ref").(string)
+
+	variables := make(map[string]string)
+	for k, v := range d.Get("variables").(map[string]interface{}) {
+		variables[k] = v.(string)
+	}
+
+	var pipeline *gitlab.Pipeline
+	var err error
+
+	if token, ok := d.GetOk("trigger_token"); ok {
+		log.Printf("[DEBUG] trigger gitlab pipeline for %q in project %s", ref, project)
+
+		pipeline, _, err = client.PipelineTriggers.RunPipelineTrigger(project, &gitlab.RunPipelineTriggerOptions{
+			Ref:       gitlab.String(ref),
+			Token:     gitlab.String(token.(string)),
+			Variables: variables,
+		}, gitlab.WithContext(ctx))
+	} else {
+		log.Printf("[DEBUG] create gitlab pipeline for %q in project %s", ref, project)
+
+		pipelineVariables := make([]*gitlab.PipelineVariable, 0, len(variables))
+		for k, v := range variables {
+			pipelineVariables = append(pipelineVariables, &gitlab.PipelineVariable{Key: k, Value: v, VariableType: "env_var"})
+		}
+
+		pipeline, _, err = client.Pipelines.CreatePipeline(project, &gitlab.CreatePipelineOptions{
+			Ref:       gitlab.String(ref),
+			Variables: &pipelineVariables,
+		}, gitlab.WithContext(ctx))
+	}
+	if err != nil {
+		return diag.Errorf("failed to run pipeline for %q in project %s: %v", ref, project, err)
+	}
+
+	// from this point onwards no matter how we return, resource creation
+	// is committed to state since we set its ID
+	pipelineID := strconv.Itoa(pipeline.ID)
+	d.SetId(buildTwoPartID(&project, &pipelineID))
+
+	if d.Get("wait_for_completion").(bool) {
+		log.Printf("[DEBUG] waiting for gitlab pipeline %d in project %s to finish", pipeline.ID, project)
+
+		waitForManual := d.Get("wait_for_manual").(bool)
+		stateConf := &resource.StateChangeConf{
+			Pending:    []string{"running"},
+			Target:     []string{"finished"},
+			Timeout:    d.Timeout(schema.TimeoutCreate),
+			MinTimeout: 5 * time.Second,
+			Refresh: func() (interface{}, string, error) {
+				current, _, err := client.Pipelines.GetPipeline(project, pipeline.ID, gitlab.WithContext(ctx))
+				if err != nil {
+					return nil, "", err
+				}
+
+				if pipelineRunFinished(current.Status, waitForManual) {
+					return current, "finished", nil
+				}
+				return current, "running", nil
+			},
+		}
+
+		result, err := stateConf.WaitForStateContext(ctx)
+		if err != nil {
+			return diag.Errorf("error while waiting for pipeline %d in project %s to finish: %v", pipeline.ID, project, err)
+		}
+
+		if status := result.(*gitlab.Pipeline).Status; status != "success" && status != "manual" {
+			if diags := resourceGitlabPipelineRunRead(ctx, d, meta); diags.HasError() {
+				return diags
+			}
+			return diag.Errorf("pipeline %d in project %s finished with status %q, see %s", pipeline.ID, project, status, pipeline.WebURL)
+		}
+	}
+
+	return resourceGitlabPipelineRunRead(ctx, d, meta)
+}
+
+func resourceGitlabPipelineRunRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	project, pipelineID, err := projectAndPipelineIDFromID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] read gitlab pipeline %d in project %s", pipelineID, project)
+
+	pipeline, _, err := client.Pipelines.GetPipeline(project, pipelineID, gitlab.WithContext(ctx))
+	if err != nil {
+		if is404(err) {
+			log.Printf("[DEBUG] gitlab pipeline %d in project %s not found, removing from state", pipelineID, project)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	jobs, err := listGitlabPipelineJobs(ctx, client, project, pipelineID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("project", project)
+	d.Set("pipeline_id", pipeline.ID)
+	d.Set("sha", pipeline.SHA)
+	d.Set("status", pipeline.Status)
+	d.Set("web_url", pipeline.WebURL)
+	if err := d.Set("jobs", flattenPipelineRunJobs(jobs)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceGitlabPipelineRunUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// Only wait_for_completion and wait_for_manual can change without running a new pipeline, and they only matter on create.
+	return resourceGitlabPipelineRunRead(ctx, d, meta)
+}
+
+func resourceGitlabPipelineRunDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// The pipeline is part of the history of the project, so it's intentionally kept.
+	log.Printf("[DEBUG] keeping gitlab pipeline %s", d.Id())
+	return nil
+}
+
+// pipelineRunFinished reports whether waiting for a pipeline with the status is over.
+func pipelineRunFinished(status string, waitForManual bool) bool {
+	if status == "manual" {
+		return !waitForManual
+	}
+	return pipelineRunFinishedStatuses[status]
+}
+
+func projectAndPipelineIDFromID(id string) (string, int, error) {
+	project, pipeline, err := parseTwoPartID(id)
+	if err != nil {
+		return "", 0, err
+	}
+
+	pipelineID, err := strconv.Atoi(pipeline)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid pipeline ID %q: %v", pipeline, err)
+	}
+
+	return project, pipelineID, nil
+}
+
+func listGitlabPipelineJobs(ctx context.Context, client *gitlab.Client, project string, pipelineID int) ([]*gitlab.Job, error) {
+	jobs := make([]*gitlab.Job, 0)
+
+	options := &gitlab.ListJobsOptions{ListOptions: gitlab.ListOptions{PerPage: 100, Page: 1}}
+	for options.Page != 0 {
+		page, resp, err := client.Jobs.ListPipelineJobs(project, pipelineID, options, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+
+		jobs = append(jobs, page...)
+		options.Page = resp.NextPage
+	}
+
+	return jobs, nil
+}
+
+func flattenPipelineRunJobs(jobs []*gitlab.Job) []map[string]interface{} {
+	values := make([]map[string]interface{}, 0, len(jobs))
+
+	for _, job := range jobs {
+		values = append(values, map[string]interface{}{
+			"id":      job.ID,
+			"name":    job.Name,
+			"stage":   job.Stage,
+			"status":  job.Status,
+			"web_url": job.WebURL,
+		})
+	}
+
+	return values
+}
@@ -0,0 +1,104 @@
+package gitlab
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/xanzy/go-gitlab"
+)
+
+func TestGitlabPipelineRun_pipelineRunFinished(t *testing.T) {
+	cases := []struct {
+		Status        string
+		WaitForManual bool
+		Expected      bool
+	}{
+		{Status: "success", Expected: true},
+		{Status: "failed", Expected: true},
+		{Status: "canceled", Expected: true},
+		{Status: "skipped", Expected: true},
+		{Status: "manual", Expected: true},
+		{Status: "manual", WaitForManual: true, Expected: false},
+		{Status: "running", Expected: false},
+		{Status: "created", Expected: false},
+		// Statuses unknown to the provider are waited for.
+		{Status: "canceling", Expected: false},
+	}
+
+	for _, tc := range cases {
+		if got := pipelineRunFinished(tc.Status, tc.WaitForManual); got != tc.Expected {
+			t.Errorf("expected pipelineRunFinished(%q, %t) to be %t, got %t", tc.Status, tc.WaitForManual, tc.Expected, got)
+		}
+	}
+}
+
+func TestAccGitlabPipelineRun_basic(t *testing.T) {
+	testAccCheck(t)
+
+	client := testAccNewClient(t)
+	project := testAccCreateProject(t, client)
+
+	_, _, err := client.RepositoryFiles.CreateFile(project.ID, ".gitlab-ci.yml", &gitlab.CreateFileOptions{
+		Branch:        gitlab.String(project.DefaultBranch),
+		Content:       gitlab.String("bootstrap:\n  stage: deploy\n  script:\n    - echo $TARGET\n"),
+		CommitMessage: gitlab.String("add ci configuration"),
+	})
+	if err != nil {
+		t.Fatalf("could not create ci configuration: %v", err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			// Run a pipeline with the pipelines API
+			{
+				Config: fmt.Sprintf(`
+resource "gitlab_pipeline_run" "this" {
+  project = %d
+  ref     = %q
+
+  variables = {
+    TARGET = "staging"
+  }
+}
+`, project.ID, project.DefaultBranch),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("gitlab_pipeline_run.this", "pipeline_id"),
+					resource.TestCheckResourceAttrSet("gitlab_pipeline_run.this", "status"),
+					resource.TestCheckResourceAttrSet("gitlab_pipeline_run.this", "web_url"),
+					resource.TestCheckResourceAttr("gitlab_pipeline_run.this", "jobs.#", "1"),
+					resource.TestCheckResourceAttr("gitlab_pipeline_run.this", "jobs.0.name", "bootstrap"),
+					resource.TestCheckResourceAttr("gitlab_pipeline_run.this", "jobs.0.stage", "deploy"),
+				),
+			},
+			// Run a new pipeline with a trigger token when the triggers change
+			{
+				Config: fmt.Sprintf(`
+resource "gitlab_pipeline_trigger" "this" {
+  project     = %d
+  description = "bootstrap"
+}
+
+resource "gitlab_pipeline_run" "this" {
+  project       = %d
+  ref           = %q
+  trigger_token = gitlab_pipeline_trigger.this.token
+
+  variables = {
+    TARGET = "staging"
+  }
+
+  triggers = {
+    version = "2"
+  }
+}
+`, project.ID, project.ID, project.DefaultBranch),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("gitlab_pipeline_run.this", "pipeline_id"),
+					resource.TestCheckResourceAttr("gitlab_pipeline_run.this", "jobs.#", "1"),
+				),
+			},
+		},
+	})
+}
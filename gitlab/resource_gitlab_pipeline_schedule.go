@@ -6,6 +6,7 @@ import (
 	"log"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -25,6 +26,7 @@ func resourceGitlabPipelineSchedule() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceGitlabPipelineScheduleStateImporter,
 		},
+		CustomizeDiff: customizeDiffCronChange([]string{"cron", "cron_timezone"}, "next_run_at"),
 
 		Schema: map[string]*schema.Schema{
 			"project": {
@@ -44,15 +46,17 @@ func resourceGitlabPipelineSchedule() *schema.Resource {
 				Required:    true,
 			},
 			"cron": {
-				Description: "The cron (e.g. `0 1 * * *`).",
-				Type:        schema.TypeString,
-				Required:    true,
+				Description:      "The cron (e.g. `0 1 * * *`). Supports lists, ranges, steps, month and weekday names, a leading seconds field, which is ignored, `L` for the last day of the month, the nth weekday of the month (e.g. `mon#2` or `fri#L`) and the `@yearly`, `@annually`, `@monthly`, `@weekly`, `@daily`, `@midnight` and `@hourly` shortcuts.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateCronExpression,
 			},
 			"cron_timezone": {
-				Description: "The timezone.",
-				Type:        schema.TypeString,
-				Optional:    true,
-				Default:     "UTC",
+				Description:      "The timezone, either of the tz database (e.g. `Europe/Berlin`) or a Rails time zone name (e.g. `Berlin`).",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "UTC",
+				ValidateDiagFunc: validateCronTimezone,
 			},
 			"active": {
 				Description: "The activation of pipeline schedule. If false is set, the pipeline schedule will deactivated initially.",
@@ -60,6 +64,50 @@ func resourceGitlabPipelineSchedule() *schema.Resource {
 				Optional:    true,
 				Default:     true,
 			},
+			"variables": {
+				Description: "Variables of the pipeline schedule. If set, the variables of the schedule are managed authoritatively, so don't use `gitlab_pipeline_schedule_variable` for the same schedule. Removing the block leaves the existing variables untouched.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"key": {
+							Description:  "Name of the variable.",
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: StringIsGitlabVariableName,
+						},
+						"value": {
+							Description: "Value of the variable.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"variable_type": {
+							Description:  "The type of the variable. Valid values are `env_var` and `file`.",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "env_var",
+							ValidateFunc: StringIsGitlabVariableType,
+						},
+					},
+				},
+			},
+			"take_ownership": {
+				Description: "Take ownership of the pipeline schedule if it's owned by another user than the one of the provider token, e.g. because the owner left. Pipelines are run as the owner of the schedule.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"owner": {
+				Description: "The ID of the user owning the pipeline schedule.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"next_run_at": {
+				Description: "The time of the next scheduled run in RFC3339 format.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -84,6 +132,12 @@ func resourceGitlabPipelineScheduleCreate(ctx context.Context, d *schema.Resourc
 
 	d.SetId(strconv.Itoa(PipelineSchedule.ID))
 
+	if v, ok := d.GetOk("variables"); ok {
+		if err := updatePipelineScheduleVariables(ctx, client, project, PipelineSchedule.ID, nil, v.(*schema.Set).List()); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	return resourceGitlabPipelineScheduleRead(ctx, d, meta)
 }
 
@@ -98,40 +152,50 @@ func resourceGitlabPipelineScheduleRead(ctx context.Context, d *schema.ResourceD
 
 	log.Printf("[DEBUG] read gitlab PipelineSchedule %s/%d", project, pipelineScheduleID)
 
-	opt := &gitlab.ListPipelineSchedulesOptions{
-		Page:    1,
-		PerPage: 20,
+	pipelineSchedule, _, err := client.PipelineSchedules.GetPipelineSchedule(project, pipelineScheduleID, gitlab.WithContext(ctx))
+	if err != nil {
+		if is404(err) {
+			log.Printf("[DEBUG] PipelineSchedule %d no longer exists in gitlab", pipelineScheduleID)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	d.Set("description", pipelineSchedule.Description)
+	d.Set("ref", pipelineSchedule.Ref)
+	d.Set("cron", pipelineSchedule.Cron)
+	d.Set("cron_timezone", pipelineSchedule.CronTimezone)
+	d.Set("active", pipelineSchedule.Active)
+
+	if err := d.Set("variables", flattenPipelineScheduleVariables(pipelineSchedule.Variables)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	ownerID := 0
+	if pipelineSchedule.Owner != nil {
+		ownerID = pipelineSchedule.Owner.ID
 	}
+	d.Set("owner", ownerID)
 
-	found := false
-	for {
-		pipelineSchedules, resp, err := client.PipelineSchedules.ListPipelineSchedules(project, opt, gitlab.WithContext(ctx))
+	// Record that ownership still has to be taken, so that the next plan shows a change.
+	takeOwnership := d.Get("take_ownership").(bool)
+	if takeOwnership {
+		currentUser, _, err := client.Users.CurrentUser(gitlab.WithContext(ctx))
 		if err != nil {
 			return diag.FromErr(err)
 		}
-		for _, pipelineSchedule := range pipelineSchedules {
-			if pipelineSchedule.ID == pipelineScheduleID {
-				d.Set("description", pipelineSchedule.Description)
-				d.Set("ref", pipelineSchedule.Ref)
-				d.Set("cron", pipelineSchedule.Cron)
-				d.Set("cron_timezone", pipelineSchedule.CronTimezone)
-				d.Set("active", pipelineSchedule.Active)
-				found = true
-				break
-			}
+		if currentUser.ID != ownerID {
+			takeOwnership = false
 		}
-
-		if found || resp.CurrentPage >= resp.TotalPages {
-			break
-		}
-
-		opt.Page = resp.NextPage
 	}
-	if !found {
-		log.Printf("[DEBUG] PipelineSchedule %d no longer exists in gitlab", pipelineScheduleID)
-		d.SetId("")
-		return nil
+	d.Set("take_ownership", takeOwnership)
+
+	nextRunAt := ""
+	if pipelineSchedule.NextRunAt != nil {
+		nextRunAt = pipelineSchedule.NextRunAt.Format(time.RFC3339)
 	}
+	d.Set("next_run_at", nextRunAt)
 
 	return nil
 }
@@ -139,13 +203,7 @@ func resourceGitlabPipelineScheduleRead(ctx context.Context, d *schema.ResourceD
 func resourceGitlabPipelineScheduleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*gitlab.Client)
 	project := d.Get("project").(string)
-	options := &gitlab.EditPipelineScheduleOptions{
-		Description:  gitlab.String(d.Get("description").(string)),
-		Ref:          gitlab.String(d.Get("ref").(string)),
-		Cron:         gitlab.String(d.Get("cron").(string)),
-		CronTimezone: gitlab.String(d.Get("cron_timezone").(string)),
-		Active:       gitlab.Bool(d.Get("active").(bool)),
-	}
+	options := &gitlab.EditPipelineScheduleOptions{}
 
 	pipelineScheduleID, err := strconv.Atoi(d.Id())
 
@@ -173,11 +231,28 @@ func resourceGitlabPipelineScheduleUpdate(ctx context.Context, d *schema.Resourc
 		options.Active = gitlab.Bool(d.Get("active").(bool))
 	}
 
-	log.Printf("[DEBUG] update gitlab PipelineSchedule %s", d.Id())
+	// Only the owner can change the pipeline schedule, so ownership is taken first.
+	if d.HasChange("take_ownership") && d.Get("take_ownership").(bool) {
+		log.Printf("[DEBUG] take ownership of gitlab PipelineSchedule %s", d.Id())
+		if _, _, err := client.PipelineSchedules.TakeOwnershipOfPipelineSchedule(project, pipelineScheduleID, gitlab.WithContext(ctx)); err != nil {
+			return diag.Errorf("failed to take ownership of pipeline schedule %q: %v", d.Id(), err)
+		}
+	}
 
-	_, _, err = client.PipelineSchedules.EditPipelineSchedule(project, pipelineScheduleID, options, gitlab.WithContext(ctx))
-	if err != nil {
-		return diag.FromErr(err)
+	if *options != (gitlab.EditPipelineScheduleOptions{}) {
+		log.Printf("[DEBUG] update gitlab PipelineSchedule %s", d.Id())
+
+		_, _, err = client.PipelineSchedules.EditPipelineSchedule(project, pipelineScheduleID, options, gitlab.WithContext(ctx))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("variables") {
+		o, n := d.GetChange("variables")
+		if err := updatePipelineScheduleVariables(ctx, client, project, pipelineScheduleID, o.(*schema.Set).List(), n.(*schema.Set).List()); err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
 	return resourceGitlabPipelineScheduleRead(ctx, d, meta)
@@ -213,3 +288,68 @@ func resourceGitlabPipelineScheduleStateImporter(ctx context.Context, d *schema.
 
 	return []*schema.ResourceData{d}, nil
 }
+
+func flattenPipelineScheduleVariables(variables []*gitlab.PipelineVariable) []map[string]interface{} {
+	values := make([]map[string]interface{}, 0, len(variables))
+
+	for _, variable := range variables {
+		values = append(values, map[string]interface{}{
+			"key":           variable.Key,
+			"value":         variable.Value,
+			"variable_type": variable.VariableType,
+		})
+	}
+
+	return values
+}
+
+// updatePipelineScheduleVariables creates, edits and deletes the variables of the pipeline schedule,
+// so that the old variables are replaced by the new ones.
+func updatePipelineScheduleVariables(ctx context.Context, client *gitlab.Client, project string, pipelineScheduleID int, oldVariables []interface{}, newVariables []interface{}) error {
+	old := make(map[string]map[string]interface{})
+	for _, v := range oldVariables {
+		variable := v.(map[string]interface{})
+		old[variable["key"].(string)] = variable
+	}
+
+	for _, v := range newVariables {
+		variable := v.(map[string]interface{})
+		key := variable["key"].(string)
+		value := variable["value"].(string)
+		variableType := variable["variable_type"].(string)
+
+		existing, ok := old[key]
+		delete(old, key)
+
+		switch {
+		case !ok:
+			log.Printf("[DEBUG] create variable %q of gitlab PipelineSchedule %d", key, pipelineScheduleID)
+			_, _, err := client.PipelineSchedules.CreatePipelineScheduleVariable(project, pipelineScheduleID, &gitlab.CreatePipelineScheduleVariableOptions{
+				Key:          gitlab.String(key),
+				Value:        gitlab.String(value),
+				VariableType: gitlab.String(variableType),
+			}, gitlab.WithContext(ctx))
+			if err != nil {
+				return fmt.Errorf("failed to create variable %q of pipeline schedule %d: %v", key, pipelineScheduleID, err)
+			}
+		case existing["value"].(string) != value || existing["variable_type"].(string) != variableType:
+			log.Printf("[DEBUG] update variable %q of gitlab PipelineSchedule %d", key, pipelineScheduleID)
+			_, _, err := client.PipelineSchedules.EditPipelineScheduleVariable(project, pipelineScheduleID, key, &gitlab.EditPipelineScheduleVariableOptions{
+				Value:        gitlab.String(value),
+				VariableType: gitlab.String(variableType),
+			}, gitlab.WithContext(ctx))
+			if err != nil {
+				return fmt.Errorf("failed to update variable %q of pipeline schedule %d: %v", key, pipelineScheduleID, err)
+			}
+		}
+	}
+
+	for key := range old {
+		log.Printf("[DEBUG] delete variable %q of gitlab PipelineSchedule %d", key, pipelineScheduleID)
+		if _, _, err := client.PipelineSchedules.DeletePipelineScheduleVariable(project, pipelineScheduleID, key, gitlab.WithContext(ctx)); err != nil {
+			return fmt.Errorf("failed to delete variable %q of pipeline schedule %d: %v", key, pipelineScheduleID, err)
+		}
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package gitlab
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
 	"testing"
 
@@ -66,6 +67,189 @@ func TestAccGitlabPipelineSchedule_basic(t *testing.T) {
 	})
 }
 
+func TestAccGitlabPipelineSchedule_variablesAndOwnership(t *testing.T) {
+	testAccCheck(t)
+
+	client := testAccNewClient(t)
+	project := testAccCreateProject(t, client)
+
+	currentUser, _, err := client.Users.CurrentUser()
+	if err != nil {
+		t.Fatalf("could not get current user: %v", err)
+	}
+
+	// A second maintainer of the project, which takes ownership of the schedule away from the provider user.
+	otherUser := testAccCreateUsers(t, client, 1)[0]
+	if _, _, err := client.ProjectMembers.AddProjectMember(project.ID, &gitlab.AddProjectMemberOptions{
+		UserID:      otherUser.ID,
+		AccessLevel: gitlab.AccessLevel(gitlab.MaintainerPermissions),
+	}); err != nil {
+		t.Fatalf("could not add test project member: %v", err)
+	}
+	token, _, err := client.Users.CreateImpersonationToken(otherUser.ID, &gitlab.CreateImpersonationTokenOptions{
+		Name:   gitlab.String("acctest"),
+		Scopes: &[]string{"api"},
+	})
+	if err != nil {
+		t.Fatalf("could not create impersonation token: %v", err)
+	}
+	otherClient, err := gitlab.NewClient(token.Token, gitlab.WithBaseURL(client.BaseURL().String()))
+	if err != nil {
+		t.Fatalf("could not initialize test client: %v", err)
+	}
+
+	var schedule gitlab.PipelineSchedule
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabPipelineScheduleDestroy,
+		Steps: []resource.TestStep{
+			// Create a schedule with inline variables
+			{
+				Config: fmt.Sprintf(`
+resource "gitlab_pipeline_schedule" "schedule" {
+  project        = %d
+  description    = "Pipeline Schedule"
+  ref            = %q
+  cron           = "0 1 * * *"
+  take_ownership = true
+
+  variables {
+    key   = "TARGET"
+    value = "staging"
+  }
+
+  variables {
+    key           = "CONFIG"
+    value         = "debug: true"
+    variable_type = "file"
+  }
+}
+`, project.ID, project.DefaultBranch),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabPipelineScheduleExists("gitlab_pipeline_schedule.schedule", &schedule),
+					resource.TestCheckResourceAttr("gitlab_pipeline_schedule.schedule", "variables.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("gitlab_pipeline_schedule.schedule", "variables.*", map[string]string{
+						"key":           "CONFIG",
+						"value":         "debug: true",
+						"variable_type": "file",
+					}),
+					resource.TestCheckResourceAttr("gitlab_pipeline_schedule.schedule", "owner", strconv.Itoa(currentUser.ID)),
+					resource.TestCheckResourceAttr("gitlab_pipeline_schedule.schedule", "take_ownership", "true"),
+					resource.TestCheckResourceAttrSet("gitlab_pipeline_schedule.schedule", "next_run_at"),
+				),
+			},
+			// Verify import
+			{
+				ResourceName:            "gitlab_pipeline_schedule.schedule",
+				ImportState:             true,
+				ImportStateIdFunc:       getPipelineScheduleImportID("gitlab_pipeline_schedule.schedule"),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"take_ownership"},
+			},
+			// Change, remove and add variables
+			{
+				Config: fmt.Sprintf(`
+resource "gitlab_pipeline_schedule" "schedule" {
+  project        = %d
+  description    = "Pipeline Schedule"
+  ref            = %q
+  cron           = "0 1 * * *"
+  take_ownership = true
+
+  variables {
+    key   = "TARGET"
+    value = "production"
+  }
+
+  variables {
+    key   = "VERBOSE"
+    value = "1"
+  }
+}
+`, project.ID, project.DefaultBranch),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlab_pipeline_schedule.schedule", "variables.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("gitlab_pipeline_schedule.schedule", "variables.*", map[string]string{
+						"key":   "TARGET",
+						"value": "production",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("gitlab_pipeline_schedule.schedule", "variables.*", map[string]string{
+						"key":   "VERBOSE",
+						"value": "1",
+					}),
+				),
+			},
+			// Take back the ownership of the schedule after another user took it
+			{
+				PreConfig: func() {
+					if _, _, err := otherClient.PipelineSchedules.TakeOwnershipOfPipelineSchedule(project.ID, schedule.ID); err != nil {
+						t.Fatalf("could not take ownership of the pipeline schedule as another user: %v", err)
+					}
+				},
+				Config: fmt.Sprintf(`
+resource "gitlab_pipeline_schedule" "schedule" {
+  project        = %d
+  description    = "Pipeline Schedule"
+  ref            = %q
+  cron           = "0 1 * * *"
+  take_ownership = true
+
+  variables {
+    key   = "TARGET"
+    value = "production"
+  }
+
+  variables {
+    key   = "VERBOSE"
+    value = "1"
+  }
+}
+`, project.ID, project.DefaultBranch),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlab_pipeline_schedule.schedule", "owner", strconv.Itoa(currentUser.ID)),
+					resource.TestCheckResourceAttr("gitlab_pipeline_schedule.schedule", "take_ownership", "true"),
+					resource.TestCheckResourceAttr("gitlab_pipeline_schedule.schedule", "variables.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccGitlabPipelineSchedule_invalidCron(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "gitlab_pipeline_schedule" "schedule" {
+  project     = "foo/bar"
+  description = "Pipeline Schedule"
+  ref         = "master"
+  cron        = "0 1 * *"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid cron expression`),
+			},
+			{
+				Config: `
+resource "gitlab_pipeline_schedule" "schedule" {
+  project       = "foo/bar"
+  description   = "Pipeline Schedule"
+  ref           = "master"
+  cron          = "0 1 * * *"
+  cron_timezone = "Mars/Olympus_Mons"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid time zone`),
+			},
+		},
+	})
+}
+
 // lintignore: AT002 // TODO: Resolve this tfproviderlint issue
 func TestAccGitlabPipelineSchedule_import(t *testing.T) {
 	rInt := acctest.RandInt()
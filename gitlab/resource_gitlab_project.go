@@ -6,15 +6,21 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	gitlab "github.com/xanzy/go-gitlab"
 )
 
+var containerExpirationPolicyCadenceValues = []string{"1d", "7d", "14d", "1month", "3month"}
+var containerExpirationPolicyKeepNValues = []int{1, 5, 10, 25, 50, 100}
+var containerExpirationPolicyOlderThanValues = []string{"7d", "14d", "30d", "90d"}
+
 var resourceGitLabProjectSchema = map[string]*schema.Schema{
 	"name": {
 		Description: "The name of the project.",
@@ -54,11 +60,38 @@ var resourceGitLabProjectSchema = map[string]*schema.Schema{
 		Optional:    true,
 		Computed:    true,
 	},
+	"delete_old_default_branch": {
+		Description: "Delete the previous default branch when `default_branch` is changed. " +
+			"The new default branch is created from the previous one if it doesn't exist yet, in which case the branch protection of the previous default branch is copied to it. An existing new default branch keeps its protection as it is. The previous default branch keeps its protection unless it's deleted.",
+		Type:     schema.TypeBool,
+		Optional: true,
+	},
 	"import_url": {
-		Description: "Git URL to a repository to be imported.",
-		Type:        schema.TypeString,
-		Optional:    true,
-		ForceNew:    true,
+		Description: "Git URL to a repository to be imported. Credentials embedded in the URL aren't stored in the state; " +
+			"use `import_url_username` and `import_url_password` to keep them out of the configuration as well.",
+		Type:     schema.TypeString,
+		Optional: true,
+		ForceNew: true,
+		StateFunc: func(v interface{}) string {
+			return stripURLCredentials(v.(string))
+		},
+		DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+			return stripURLCredentials(old) == stripURLCredentials(new)
+		},
+	},
+	"import_url_username": {
+		Description:  "The username used to authenticate against `import_url`. It's merged into the URL when it's sent to GitLab.",
+		Type:         schema.TypeString,
+		Optional:     true,
+		Sensitive:    true,
+		RequiredWith: []string{"import_url"},
+	},
+	"import_url_password": {
+		Description:  "The password or token used to authenticate against `import_url`. It's merged into the URL when it's sent to GitLab.",
+		Type:         schema.TypeString,
+		Optional:     true,
+		Sensitive:    true,
+		RequiredWith: []string{"import_url"},
 	},
 	"request_access_enabled": {
 		Description: "Allow users to request member access.",
@@ -108,6 +141,63 @@ var resourceGitLabProjectSchema = map[string]*schema.Schema{
 		Optional:    true,
 		Default:     true,
 	},
+	"container_expiration_policy": {
+		Description: "Set the image cleanup policy for the container registry of the project.",
+		Type:        schema.TypeList,
+		MaxItems:    1,
+		Optional:    true,
+		Computed:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"enabled": {
+					Description: "If true, the policy is enabled.",
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Computed:    true,
+				},
+				"cadence": {
+					Description:  "The cadence of the policy. Valid values are `1d`, `7d`, `14d`, `1month` and `3month`.",
+					Type:         schema.TypeString,
+					Optional:     true,
+					Computed:     true,
+					ValidateFunc: validation.StringInSlice(containerExpirationPolicyCadenceValues, false),
+				},
+				"keep_n": {
+					Description:  "The number of images to keep. Valid values are `1`, `5`, `10`, `25`, `50` and `100`.",
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Computed:     true,
+					ValidateFunc: validation.IntInSlice(containerExpirationPolicyKeepNValues),
+				},
+				"older_than": {
+					Description:  "The number of days to keep images. Valid values are `7d`, `14d`, `30d` and `90d`.",
+					Type:         schema.TypeString,
+					Optional:     true,
+					Computed:     true,
+					ValidateFunc: validation.StringInSlice(containerExpirationPolicyOlderThanValues, false),
+				},
+				"name_regex_delete": {
+					Description:  "The regular expression to match image names to delete.",
+					Type:         schema.TypeString,
+					Optional:     true,
+					Computed:     true,
+					ValidateFunc: validation.StringIsValidRegExp,
+				},
+				"name_regex_keep": {
+					Description:  "The regular expression to match image names to keep.",
+					Type:         schema.TypeString,
+					Optional:     true,
+					Computed:     true,
+					ValidateFunc: validation.StringIsValidRegExp,
+				},
+				"next_run_at": {
+					Description: "The next time the policy will run.",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+			},
+		},
+	},
 	"lfs_enabled": {
 		Description: "Enable LFS for the project.",
 		Type:        schema.TypeBool,
@@ -174,12 +264,39 @@ var resourceGitLabProjectSchema = map[string]*schema.Schema{
 		Computed:    true,
 	},
 	"tags": {
-		Description: "Tags (topics) of the project.",
-		Type:        schema.TypeSet,
+		Description:   "Tags (topics) of the project.",
+		Type:          schema.TypeSet,
+		Optional:      true,
+		Computed:      true,
+		ForceNew:      false,
+		Elem:          &schema.Schema{Type: schema.TypeString},
+		Set:           schema.HashString,
+		Deprecated:    "Use `topics` instead. To migrate, rename `tags` to `topics`, which doesn't change the project.",
+		ConflictsWith: []string{"topics"},
+	},
+	"topics": {
+		Description:   "The list of topics for the project. Removing it, and the deprecated `tags`, clears the topics.",
+		Type:          schema.TypeSet,
+		Optional:      true,
+		Computed:      true,
+		Elem:          &schema.Schema{Type: schema.TypeString},
+		Set:           schema.HashString,
+		ConflictsWith: []string{"tags"},
+	},
+	"avatar": {
+		Description: "A local path to the avatar image to upload. **Note**: not available for imported resources. Remove it to delete the avatar of the project.",
+		Type:        schema.TypeString,
 		Optional:    true,
-		ForceNew:    false,
-		Elem:        &schema.Schema{Type: schema.TypeString},
-		Set:         schema.HashString,
+	},
+	"avatar_hash": {
+		Description: "The SHA256 hash of the local avatar image, which is used to detect changes of the image.",
+		Type:        schema.TypeString,
+		Computed:    true,
+	},
+	"avatar_url": {
+		Description: "The URL of the avatar image of the project.",
+		Type:        schema.TypeString,
+		Computed:    true,
 	},
 	"archived": {
 		Description: "Whether the project is in read-only mode (archived). Repositories can be archived/unarchived by toggling this parameter.",
@@ -358,6 +475,162 @@ var resourceGitLabProjectSchema = map[string]*schema.Schema{
 		Type:        schema.TypeString,
 		Optional:    true,
 	},
+	"build_timeout": {
+		Description:  "The maximum amount of time, in seconds, that a job can run.",
+		Type:         schema.TypeInt,
+		Optional:     true,
+		Computed:     true,
+		ValidateFunc: validation.IntBetween(600, 2592000),
+	},
+	"ci_default_git_depth": {
+		Description:  "Default number of revisions for shallow cloning.",
+		Type:         schema.TypeInt,
+		Optional:     true,
+		Computed:     true,
+		ValidateFunc: validation.IntBetween(0, 1000),
+	},
+	"auto_cancel_pending_pipelines": {
+		Description:  "Auto-cancel pending pipelines. This isn't a boolean, but `enabled` or `disabled`.",
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		ValidateFunc: validation.StringInSlice([]string{"enabled", "disabled"}, false),
+	},
+	"ci_forward_deployment_enabled": {
+		Description: "When a new deployment job starts, skip older deployment jobs that are still pending. Requires GitLab 13.8 or later.",
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Computed:    true,
+	},
+	"keep_latest_artifact": {
+		Description: "Keep the artifacts of the most recent successful jobs. Requires GitLab 13.9 or later.",
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Computed:    true,
+	},
+	"auto_devops_enabled": {
+		Description: "Enable Auto DevOps for this project.",
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Computed:    true,
+	},
+	"auto_devops_deploy_strategy": {
+		Description:  "Auto Deploy strategy. Valid values are `continuous`, `manual` and `timed_incremental`.",
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		ValidateFunc: validation.StringInSlice([]string{"continuous", "manual", "timed_incremental"}, false),
+	},
+	"public_builds": {
+		Description: "If true, jobs can be viewed by non-project members.",
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Computed:    true,
+	},
+	"restrict_user_defined_variables": {
+		Description: "Allow only users with the Maintainer role to pass user-defined variables when triggering a pipeline. Requires GitLab 13.8 or later.",
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Computed:    true,
+	},
+	"merge_pipelines_enabled": {
+		Description: "Enable or disable merged results pipelines (enterprise edition).",
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Computed:    true,
+	},
+	"merge_trains_enabled": {
+		Description: "Enable or disable merge trains. Requires `merge_pipelines_enabled` to be set to `true` to take effect (enterprise edition).",
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Computed:    true,
+	},
+	"merge_commit_template": {
+		Description: "Template used to create merge commit message in merge requests. Requires GitLab 14.5 or later.",
+		Type:        schema.TypeString,
+		Optional:    true,
+		Computed:    true,
+	},
+	"squash_commit_template": {
+		Description: "Template used to create squash commit message in merge requests. Requires GitLab 14.6 or later.",
+		Type:        schema.TypeString,
+		Optional:    true,
+		Computed:    true,
+	},
+	"suggestion_commit_message": {
+		Description: "The commit message used to apply merge request suggestions.",
+		Type:        schema.TypeString,
+		Optional:    true,
+		Computed:    true,
+	},
+	"resolve_outdated_diff_discussions": {
+		Description: "Automatically resolve merge request diffs discussions on lines changed with a push.",
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Computed:    true,
+	},
+	"printing_merge_request_link_enabled": {
+		Description: "Show link to create or view a merge request when pushing from the command line.",
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Computed:    true,
+	},
+	"autoclose_referenced_issues": {
+		Description: "Set whether auto-closing referenced issues on default branch.",
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Computed:    true,
+	},
+	"forked_from_project_id": {
+		Description:   "The ID of the project to fork from. If set on creation, the project is created as a fork of this project in `namespace_id`. Changing it on an existing project links the project as a fork of the given project, and removing it unlinks the fork relationship.",
+		Type:          schema.TypeInt,
+		Optional:      true,
+		ConflictsWith: []string{"import_url", "template_name", "template_project_id", "initialize_with_readme"},
+	},
+	"forked_from_project": {
+		Description: "Present if the project is a fork. Contains information about the upstream project.",
+		Type:        schema.TypeList,
+		Computed:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"http_url_to_repo": {
+					Description: "The HTTP clone URL of the upstream project.",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+				"id": {
+					Description: "The ID of the upstream project.",
+					Type:        schema.TypeInt,
+					Computed:    true,
+				},
+				"name": {
+					Description: "The name of the upstream project.",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+				"name_with_namespace": {
+					Description: "In `group / subgroup / project` or `user / project` format.",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+				"path": {
+					Description: "The path of the upstream project.",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+				"path_with_namespace": {
+					Description: "In `group/subgroup/project` or `user/project` format.",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+				"web_url": {
+					Description: "The web url of the upstream project.",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+			},
+		},
+	},
 }
 
 func resourceGitlabProject() *schema.Resource {
@@ -371,10 +644,68 @@ func resourceGitlabProject() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
-		Schema: resourceGitLabProjectSchema,
+		Schema:        resourceGitLabProjectSchema,
+		CustomizeDiff: customdiff.All(customizeDiffAvatarHash, resourceGitlabProjectCustomizeDiffTopics),
 	}
 }
 
+// projectSettingRequirement describes what a project setting requires from the GitLab instance.
+type projectSettingRequirement struct {
+	minVersion string
+	enterprise bool
+}
+
+// projectSettingsRequirements are the requirements of the project settings
+// that aren't available in all supported GitLab versions and editions.
+var projectSettingsRequirements = map[string]projectSettingRequirement{
+	"ci_forward_deployment_enabled":   {minVersion: "13.8"},
+	"keep_latest_artifact":            {minVersion: "13.9"},
+	"restrict_user_defined_variables": {minVersion: "13.8"},
+	"merge_pipelines_enabled":         {enterprise: true},
+	"merge_trains_enabled":            {enterprise: true},
+	"merge_commit_template":           {minVersion: "14.5"},
+	"squash_commit_template":          {minVersion: "14.6"},
+}
+
+// gitlabProject extends the project of go-gitlab with the attributes that aren't exposed by it.
+type gitlabProject struct {
+	gitlab.Project
+	BuildTimeout                    int    `json:"build_timeout"`
+	AutoCancelPendingPipelines      string `json:"auto_cancel_pending_pipelines"`
+	KeepLatestArtifact              bool   `json:"keep_latest_artifact"`
+	AutoDevopsEnabled               bool   `json:"auto_devops_enabled"`
+	AutoDevopsDeployStrategy        string `json:"auto_devops_deploy_strategy"`
+	RestrictUserDefinedVariables    bool   `json:"restrict_user_defined_variables"`
+	MergePipelinesEnabled           bool   `json:"merge_pipelines_enabled"`
+	MergeTrainsEnabled              bool   `json:"merge_trains_enabled"`
+	MergeCommitTemplate             string `json:"merge_commit_template"`
+	SquashCommitTemplate            string `json:"squash_commit_template"`
+	PrintingMergeRequestLinkEnabled bool   `json:"printing_merge_request_link_enabled"`
+	ImportURL                       string `json:"import_url"`
+}
+
+// gitlabProjectSettingsOptions are the CI/CD and merge request settings of a project,
+// some of which aren't supported by go-gitlab's EditProjectOptions.
+type gitlabProjectSettingsOptions struct {
+	BuildTimeout                    *int    `url:"build_timeout,omitempty" json:"build_timeout,omitempty"`
+	CIDefaultGitDepth               *int    `url:"ci_default_git_depth,omitempty" json:"ci_default_git_depth,omitempty"`
+	AutoCancelPendingPipelines      *string `url:"auto_cancel_pending_pipelines,omitempty" json:"auto_cancel_pending_pipelines,omitempty"`
+	CIForwardDeploymentEnabled      *bool   `url:"ci_forward_deployment_enabled,omitempty" json:"ci_forward_deployment_enabled,omitempty"`
+	KeepLatestArtifact              *bool   `url:"keep_latest_artifact,omitempty" json:"keep_latest_artifact,omitempty"`
+	AutoDevopsEnabled               *bool   `url:"auto_devops_enabled,omitempty" json:"auto_devops_enabled,omitempty"`
+	AutoDevopsDeployStrategy        *string `url:"auto_devops_deploy_strategy,omitempty" json:"auto_devops_deploy_strategy,omitempty"`
+	PublicBuilds                    *bool   `url:"public_builds,omitempty" json:"public_builds,omitempty"`
+	RestrictUserDefinedVariables    *bool   `url:"restrict_user_defined_variables,omitempty" json:"restrict_user_defined_variables,omitempty"`
+	MergePipelinesEnabled           *bool   `url:"merge_pipelines_enabled,omitempty" json:"merge_pipelines_enabled,omitempty"`
+	MergeTrainsEnabled              *bool   `url:"merge_trains_enabled,omitempty" json:"merge_trains_enabled,omitempty"`
+	MergeCommitTemplate             *string `url:"merge_commit_template,omitempty" json:"merge_commit_template,omitempty"`
+	SquashCommitTemplate            *string `url:"squash_commit_template,omitempty" json:"squash_commit_template,omitempty"`
+	SuggestionCommitMessage         *string `url:"suggestion_commit_message,omitempty" json:"suggestion_commit_message,omitempty"`
+	ResolveOutdatedDiffDiscussions  *bool   `url:"resolve_outdated_diff_discussions,omitempty" json:"resolve_outdated_diff_discussions,omitempty"`
+	PrintingMergeRequestLinkEnabled *bool   `url:"printing_merge_request_link_enabled,omitempty" json:"printing_merge_request_link_enabled,omitempty"`
+	AutocloseReferencedIssues       *bool   `url:"autoclose_referenced_issues,omitempty" json:"autoclose_referenced_issues,omitempty"`
+}
+
 func resourceGitlabProjectSetToState(d *schema.ResourceData, project *gitlab.Project) error {
 	d.SetId(fmt.Sprintf("%d", project.ID))
 	d.Set("name", project.Name)
@@ -390,6 +721,9 @@ func resourceGitlabProjectSetToState(d *schema.ResourceData, project *gitlab.Pro
 	d.Set("wiki_enabled", project.WikiEnabled)
 	d.Set("snippets_enabled", project.SnippetsEnabled)
 	d.Set("container_registry_enabled", project.ContainerRegistryEnabled)
+	if err := d.Set("container_expiration_policy", flattenProjectContainerExpirationPolicy(project.ContainerExpirationPolicy)); err != nil {
+		return err
+	}
 	d.Set("lfs_enabled", project.LFSEnabled)
 	d.Set("visibility_level", string(project.Visibility))
 	d.Set("merge_method", string(project.MergeMethod))
@@ -405,6 +739,15 @@ func resourceGitlabProjectSetToState(d *schema.ResourceData, project *gitlab.Pro
 	if err := d.Set("tags", project.TagList); err != nil {
 		return err
 	}
+	// The topics of a project are returned as tag list by GitLab versions before 14.0.
+	topics := project.Topics
+	if topics == nil {
+		topics = project.TagList
+	}
+	if err := d.Set("topics", topics); err != nil {
+		return err
+	}
+	d.Set("avatar_url", project.AvatarURL)
 	d.Set("archived", project.Archived)
 	d.Set("squash_option", project.SquashOption)
 	d.Set("remove_source_branch_after_merge", project.RemoveSourceBranchAfterMerge)
@@ -418,6 +761,20 @@ func resourceGitlabProjectSetToState(d *schema.ResourceData, project *gitlab.Pro
 	d.Set("issues_template", project.IssuesTemplate)
 	d.Set("merge_requests_template", project.MergeRequestsTemplate)
 	d.Set("ci_config_path", project.CIConfigPath)
+	d.Set("ci_default_git_depth", project.CIDefaultGitDepth)
+	d.Set("ci_forward_deployment_enabled", project.CIForwardDeploymentEnabled)
+	d.Set("public_builds", project.PublicBuilds)
+	d.Set("suggestion_commit_message", project.SuggestionCommitMessage)
+	d.Set("resolve_outdated_diff_discussions", project.ResolveOutdatedDiffDiscussions)
+	d.Set("autoclose_referenced_issues", project.AutocloseReferencedIssues)
+	if project.ForkedFromProject != nil {
+		d.Set("forked_from_project_id", project.ForkedFromProject.ID)
+	} else {
+		d.Set("forked_from_project_id", 0)
+	}
+	if err := d.Set("forked_from_project", flattenForkedFromProject(project.ForkedFromProject)); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -470,12 +827,16 @@ func resourceGitlabProjectCreate(ctx context.Context, d *schema.ResourceData, me
 		options.TagList = stringSetToStringSlice(v.(*schema.Set))
 	}
 
+	if v, ok := d.GetOk("topics"); ok {
+		options.Topics = stringSetToStringSlice(v.(*schema.Set))
+	}
+
 	if v, ok := d.GetOk("initialize_with_readme"); ok {
 		options.InitializeWithReadme = gitlab.Bool(v.(bool))
 	}
 
-	if v, ok := d.GetOk("import_url"); ok {
-		options.ImportURL = gitlab.String(v.(string))
+	if _, ok := d.GetOk("import_url"); ok {
+		options.ImportURL = gitlab.String(expandProjectImportURL(d))
 	}
 
 	if v, ok := d.GetOk("template_name"); ok {
@@ -502,9 +863,27 @@ func resourceGitlabProjectCreate(ctx context.Context, d *schema.ResourceData, me
 		options.CIConfigPath = gitlab.String(v.(string))
 	}
 
-	log.Printf("[DEBUG] create gitlab project %q", *options.Name)
+	forkedFromProjectID, isFork := d.GetOk("forked_from_project_id")
+
+	var project *gitlab.Project
+	var err error
+	if isFork {
+		forkOptions := &gitlab.ForkProjectOptions{
+			Name: options.Name,
+			Path: options.Path,
+		}
+		if options.NamespaceID != nil {
+			forkOptions.Namespace = gitlab.String(fmt.Sprintf("%d", *options.NamespaceID))
+		}
+
+		log.Printf("[DEBUG] fork gitlab project %d as %q", forkedFromProjectID.(int), *options.Name)
+
+		project, _, err = client.Projects.ForkProject(forkedFromProjectID.(int), forkOptions, gitlab.WithContext(ctx))
+	} else {
+		log.Printf("[DEBUG] create gitlab project %q", *options.Name)
 
-	project, _, err := client.Projects.CreateProject(options, gitlab.WithContext(ctx))
+		project, _, err = client.Projects.CreateProject(options, gitlab.WithContext(ctx))
+	}
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -513,7 +892,7 @@ func resourceGitlabProjectCreate(ctx context.Context, d *schema.ResourceData, me
 	// is committed to state since we set its ID
 	d.SetId(fmt.Sprintf("%d", project.ID))
 
-	// An import can be triggered by import_url or by creating the project from a template.
+	// An import can be triggered by import_url, by creating the project from a template or by forking.
 	if project.ImportStatus != "none" {
 		log.Printf("[DEBUG] waiting for project %q import to finish", *options.Name)
 
@@ -542,6 +921,15 @@ func resourceGitlabProjectCreate(ctx context.Context, d *schema.ResourceData, me
 		}
 	}
 
+	// A fork is created with the settings of its upstream project,
+	// so the configured settings are applied afterwards.
+	if isFork {
+		log.Printf("[DEBUG] apply settings to forked project %q", d.Id())
+		if _, _, err := client.Projects.EditProject(d.Id(), expandProjectForkEditOptions(d), gitlab.WithContext(ctx)); err != nil {
+			return diag.Errorf("Failed to apply settings to forked project %q: %s", d.Id(), err)
+		}
+	}
+
 	if d.Get("archived").(bool) {
 		// strange as it may seem, this project is created in archived state...
 		if _, _, err := client.Projects.ArchiveProject(d.Id(), gitlab.WithContext(ctx)); err != nil {
@@ -565,45 +953,11 @@ func resourceGitlabProjectCreate(ctx context.Context, d *schema.ResourceData, me
 	// If the branch does not exist, the update will fail, so we also create it here.
 	// See: https://gitlab.com/gitlab-org/gitlab/-/issues/333426
 	// This logic may be removed when the above issue is resolved.
-	if v, ok := d.GetOk("default_branch"); ok && project.DefaultBranch != "" && project.DefaultBranch != v.(string) {
-		oldDefaultBranch := project.DefaultBranch
-		newDefaultBranch := v.(string)
-
-		log.Printf("[DEBUG] create branch %q for project %q", newDefaultBranch, d.Id())
-		_, _, err := client.Branches.CreateBranch(project.ID, &gitlab.CreateBranchOptions{
-			Branch: gitlab.String(newDefaultBranch),
-			Ref:    gitlab.String(oldDefaultBranch),
-		}, gitlab.WithContext(ctx))
-		if err != nil {
-			return diag.Errorf("Failed to create branch %q for project %q: %s", newDefaultBranch, d.Id(), err)
-		}
-
-		log.Printf("[DEBUG] set new default branch to %q for project %q", newDefaultBranch, d.Id())
-		_, _, err = client.Projects.EditProject(project.ID, &gitlab.EditProjectOptions{
-			DefaultBranch: gitlab.String(newDefaultBranch),
-		}, gitlab.WithContext(ctx))
-		if err != nil {
-			return diag.Errorf("Failed to set default branch to %q for project %q: %s", newDefaultBranch, d.Id(), err)
-		}
-
-		log.Printf("[DEBUG] protect new default branch %q for project %q", newDefaultBranch, d.Id())
-		_, _, err = client.ProtectedBranches.ProtectRepositoryBranches(project.ID, &gitlab.ProtectRepositoryBranchesOptions{
-			Name: gitlab.String(newDefaultBranch),
-		}, gitlab.WithContext(ctx))
-		if err != nil {
-			return diag.Errorf("Failed to protect default branch %q for project %q: %s", newDefaultBranch, d.Id(), err)
-		}
-
-		log.Printf("[DEBUG] unprotect old default branch %q for project %q", oldDefaultBranch, d.Id())
-		_, err = client.ProtectedBranches.UnprotectRepositoryBranches(project.ID, oldDefaultBranch, gitlab.WithContext(ctx))
-		if err != nil {
-			return diag.Errorf("Failed to unprotect undesired default branch %q for project %q: %s", oldDefaultBranch, d.Id(), err)
-		}
-
-		log.Printf("[DEBUG] delete old default branch %q for project %q", oldDefaultBranch, d.Id())
-		_, err = client.Branches.DeleteBranch(project.ID, oldDefaultBranch, gitlab.WithContext(ctx))
-		if err != nil {
-			return diag.Errorf("Failed to clean up undesired default branch %q for project %q: %s", oldDefaultBranch, d.Id(), err)
+	// A fork already has the branches of its upstream project, so its default branch is set along with the other settings.
+	if v, ok := d.GetOk("default_branch"); ok && !isFork && project.DefaultBranch != "" && project.DefaultBranch != v.(string) {
+		// The initial default branch is an artifact of the project creation, so it's always cleaned up.
+		if err := changeGitlabProjectDefaultBranch(ctx, client, d.Id(), project.DefaultBranch, v.(string), true); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
@@ -611,12 +965,12 @@ func resourceGitlabProjectCreate(ctx context.Context, d *schema.ResourceData, me
 
 	if v, ok := d.GetOk("mirror_overwrites_diverged_branches"); ok {
 		editProjectOptions.MirrorOverwritesDivergedBranches = gitlab.Bool(v.(bool))
-		editProjectOptions.ImportURL = gitlab.String(d.Get("import_url").(string))
+		editProjectOptions.ImportURL = gitlab.String(expandProjectImportURL(d))
 	}
 
 	if v, ok := d.GetOk("only_mirror_protected_branches"); ok {
 		editProjectOptions.OnlyMirrorProtectedBranches = gitlab.Bool(v.(bool))
-		editProjectOptions.ImportURL = gitlab.String(d.Get("import_url").(string))
+		editProjectOptions.ImportURL = gitlab.String(expandProjectImportURL(d))
 	}
 
 	if v, ok := d.GetOk("issues_template"); ok {
@@ -627,12 +981,30 @@ func resourceGitlabProjectCreate(ctx context.Context, d *schema.ResourceData, me
 		editProjectOptions.MergeRequestsTemplate = gitlab.String(v.(string))
 	}
 
+	if v, ok := d.GetOk("avatar"); ok {
+		log.Printf("[DEBUG] upload avatar %q for project %q", v.(string), d.Id())
+		if err := updateGitlabAvatar(ctx, client, fmt.Sprintf("projects/%s", d.Id()), v.(string)); err != nil {
+			return diag.Errorf("Failed to upload avatar for project %q: %s", d.Id(), err)
+		}
+	}
+
+	if _, ok := d.GetOk("container_expiration_policy"); ok {
+		editProjectOptions.ContainerExpirationPolicyAttributes = expandProjectContainerExpirationPolicyAttributes(d)
+	}
+
 	if (editProjectOptions != gitlab.EditProjectOptions{}) {
 		if _, _, err := client.Projects.EditProject(d.Id(), &editProjectOptions, gitlab.WithContext(ctx)); err != nil {
 			return diag.Errorf("Could not update project %q: %s", d.Id(), err)
 		}
 	}
 
+	// Not all CI/CD and merge request settings can be set during creation, so they are all applied afterwards.
+	if settingsOptions, keys := expandProjectSettingsOptions(d, projectSettingConfigured(d)); len(keys) > 0 {
+		if err := editGitlabProjectSettings(ctx, client, d.Id(), settingsOptions, keys); err != nil {
+			return diag.Errorf("Could not update CI/CD and merge request settings of project %q: %s", d.Id(), err)
+		}
+	}
+
 	return resourceGitlabProjectRead(ctx, d, meta)
 }
 
@@ -640,7 +1012,7 @@ func resourceGitlabProjectRead(ctx context.Context, d *schema.ResourceData, meta
 	client := meta.(*gitlab.Client)
 	log.Printf("[DEBUG] read gitlab project %s", d.Id())
 
-	project, _, err := client.Projects.GetProject(d.Id(), nil, gitlab.WithContext(ctx))
+	project, err := getGitlabProject(ctx, client, d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -650,10 +1022,27 @@ func resourceGitlabProjectRead(ctx context.Context, d *schema.ResourceData, meta
 		return nil
 	}
 
-	if err := resourceGitlabProjectSetToState(d, project); err != nil {
+	if err := resourceGitlabProjectSetToState(d, &project.Project); err != nil {
 		return diag.FromErr(err)
 	}
 
+	d.Set("build_timeout", project.BuildTimeout)
+	d.Set("auto_cancel_pending_pipelines", project.AutoCancelPendingPipelines)
+	d.Set("keep_latest_artifact", project.KeepLatestArtifact)
+	d.Set("auto_devops_enabled", project.AutoDevopsEnabled)
+	d.Set("auto_devops_deploy_strategy", project.AutoDevopsDeployStrategy)
+	d.Set("restrict_user_defined_variables", project.RestrictUserDefinedVariables)
+	// GitLab only returns the import URL to users with sufficient permissions, and redacts its credentials.
+	// It's only refreshed when it's managed, because projects created from templates have an import URL, too.
+	if project.ImportURL != "" && d.Get("import_url").(string) != "" {
+		d.Set("import_url", stripURLCredentials(project.ImportURL))
+	}
+	d.Set("merge_pipelines_enabled", project.MergePipelinesEnabled)
+	d.Set("merge_trains_enabled", project.MergeTrainsEnabled)
+	d.Set("merge_commit_template", project.MergeCommitTemplate)
+	d.Set("squash_commit_template", project.SquashCommitTemplate)
+	d.Set("printing_merge_request_link_enabled", project.PrintingMergeRequestLinkEnabled)
+
 	log.Printf("[DEBUG] read gitlab project %q push rules", d.Id())
 
 	pushRules, _, err := client.Projects.GetProjectPushRules(d.Id(), gitlab.WithContext(ctx))
@@ -673,228 +1062,650 @@ func resourceGitlabProjectRead(ctx context.Context, d *schema.ResourceData, meta
 func resourceGitlabProjectUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*gitlab.Client)
 
-	options := &gitlab.EditProjectOptions{}
+	options := expandEditProjectOptions(d, d.HasChange)
 	transferOptions := &gitlab.TransferProjectOptions{}
 
-	if d.HasChange("name") {
-		options.Name = gitlab.String(d.Get("name").(string))
+	if d.HasChange("namespace_id") {
+		transferOptions.Namespace = gitlab.Int(d.Get("namespace_id").(int))
 	}
 
-	if d.HasChange("path") && (d.Get("path").(string) != "") {
-		options.Path = gitlab.String(d.Get("path").(string))
+	if *options != (gitlab.EditProjectOptions{}) {
+		log.Printf("[DEBUG] update gitlab project %s", d.Id())
+		_, _, err := client.Projects.EditProject(d.Id(), options, gitlab.WithContext(ctx))
+		if err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
-	if d.HasChange("namespace_id") {
-		transferOptions.Namespace = gitlab.Int(d.Get("namespace_id").(int))
+	if d.HasChange("default_branch") {
+		oldDefaultBranch, newDefaultBranch := d.GetChange("default_branch")
+		if err := changeGitlabProjectDefaultBranch(ctx, client, d.Id(), oldDefaultBranch.(string), newDefaultBranch.(string), d.Get("delete_old_default_branch").(bool)); err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
-	if d.HasChange("description") {
-		options.Description = gitlab.String(d.Get("description").(string))
+	if settingsOptions, keys := expandProjectSettingsOptions(d, d.HasChange); len(keys) > 0 {
+		log.Printf("[DEBUG] update CI/CD and merge request settings of gitlab project %s", d.Id())
+		if err := editGitlabProjectSettings(ctx, client, d.Id(), settingsOptions, keys); err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
-	if d.HasChange("default_branch") {
-		options.DefaultBranch = gitlab.String(d.Get("default_branch").(string))
+	if *transferOptions != (gitlab.TransferProjectOptions{}) {
+		log.Printf("[DEBUG] transferring project %s to namespace %d", d.Id(), transferOptions.Namespace)
+		_, _, err := client.Projects.TransferProject(d.Id(), transferOptions, gitlab.WithContext(ctx))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("forked_from_project_id") {
+		oldForkedFromProjectID, newForkedFromProjectID := d.GetChange("forked_from_project_id")
+
+		if oldForkedFromProjectID.(int) != 0 {
+			log.Printf("[DEBUG] unlink project %s as fork of project %d", d.Id(), oldForkedFromProjectID.(int))
+			if _, err := client.Projects.DeleteProjectForkRelation(d.Id(), gitlab.WithContext(ctx)); err != nil {
+				return diag.Errorf("Failed to remove fork relationship of project %q: %s", d.Id(), err)
+			}
+		}
+
+		if newForkedFromProjectID.(int) != 0 {
+			log.Printf("[DEBUG] link project %s as fork of project %d", d.Id(), newForkedFromProjectID.(int))
+			if _, _, err := client.Projects.CreateProjectForkRelation(d.Id(), newForkedFromProjectID.(int), gitlab.WithContext(ctx)); err != nil {
+				return diag.Errorf("Failed to link project %q as fork of project %d: %s", d.Id(), newForkedFromProjectID.(int), err)
+			}
+		}
+	}
+
+	if d.HasChanges("avatar", "avatar_hash") {
+		log.Printf("[DEBUG] update avatar of project %s", d.Id())
+		if err := updateGitlabAvatar(ctx, client, fmt.Sprintf("projects/%s", d.Id()), d.Get("avatar").(string)); err != nil {
+			return diag.Errorf("Failed to update avatar of project %q: %s", d.Id(), err)
+		}
+	}
+
+	if d.HasChange("archived") {
+		if d.Get("archived").(bool) {
+			if _, _, err := client.Projects.ArchiveProject(d.Id(), gitlab.WithContext(ctx)); err != nil {
+				return diag.Errorf("project %q could not be archived: %s", d.Id(), err)
+			}
+		} else {
+			if _, _, err := client.Projects.UnarchiveProject(d.Id(), gitlab.WithContext(ctx)); err != nil {
+				return diag.Errorf("project %q could not be unarchived: %s", d.Id(), err)
+			}
+		}
+	}
+
+	if d.HasChange("push_rules") {
+		err := editOrAddPushRules(ctx, client, d.Id(), d)
+		var httpError *gitlab.ErrorResponse
+		if errors.As(err, &httpError) && httpError.Response.StatusCode == http.StatusNotFound {
+			log.Printf("[DEBUG] Failed to get push rules for project %q: %v", d.Id(), err)
+			return diag.Errorf("Project push rules are not supported in your version of GitLab")
+		}
+		if err != nil {
+			return diag.Errorf("Failed to edit push rules for project %q: %s", d.Id(), err)
+		}
 	}
 
-	if d.HasChange("visibility_level") {
+	return resourceGitlabProjectRead(ctx, d, meta)
+}
+
+func resourceGitlabProjectDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	log.Printf("[DEBUG] Delete gitlab project %s", d.Id())
+
+	_, err := client.Projects.DeleteProject(d.Id(), gitlab.WithContext(ctx))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Wait for the project to be deleted.
+	// Deleting a project in gitlab is async.
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"Deleting"},
+		Target:  []string{"Deleted"},
+		Refresh: func() (interface{}, string, error) {
+			out, response, err := client.Projects.GetProject(d.Id(), nil, gitlab.WithContext(ctx))
+			if err != nil {
+				if response.StatusCode == 404 {
+					return out, "Deleted", nil
+				}
+				log.Printf("[ERROR] Received error: %#v", err)
+				return out, "Error", err
+			}
+			if out.MarkedForDeletionAt != nil {
+				// Represents a Gitlab EE soft-delete
+				return out, "Deleted", nil
+			}
+			return out, "Deleting", nil
+		},
+
+		Timeout:    10 * time.Minute,
+		MinTimeout: 3 * time.Second,
+		Delay:      5 * time.Second,
+	}
+
+	_, err = stateConf.WaitForStateContext(ctx)
+	if err != nil {
+		return diag.Errorf("error waiting for project (%s) to become deleted: %s", d.Id(), err)
+	}
+	return nil
+}
+
+// changeGitlabProjectDefaultBranch changes the default branch of a project from oldBranch to newBranch.
+// Setting a default branch which doesn't exist fails, so it's created from the old default branch first.
+// See: https://gitlab.com/gitlab-org/gitlab/-/issues/333426
+// The protection settings of the old default branch are copied to the new one, unless it's already protected.
+// The old default branch is only unprotected if it's deleted.
+func changeGitlabProjectDefaultBranch(ctx context.Context, client *gitlab.Client, pid string, oldBranch, newBranch string, deleteOldBranch bool) error {
+	created := false
+	if oldBranch != "" && newBranch != "" {
+		_, _, err := client.Branches.GetBranch(pid, newBranch, gitlab.WithContext(ctx))
+		if err != nil {
+			if !is404(err) {
+				return fmt.Errorf("failed to get branch %q of project %q: %w", newBranch, pid, err)
+			}
+
+			log.Printf("[DEBUG] create branch %q for project %q", newBranch, pid)
+			_, _, err := client.Branches.CreateBranch(pid, &gitlab.CreateBranchOptions{
+				Branch: gitlab.String(newBranch),
+				Ref:    gitlab.String(oldBranch),
+			}, gitlab.WithContext(ctx))
+			if err != nil {
+				return fmt.Errorf("failed to create branch %q for project %q: %w", newBranch, pid, err)
+			}
+			created = true
+		}
+	}
+
+	log.Printf("[DEBUG] set new default branch to %q for project %q", newBranch, pid)
+	_, _, err := client.Projects.EditProject(pid, &gitlab.EditProjectOptions{
+		DefaultBranch: gitlab.String(newBranch),
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to set default branch to %q for project %q: %w", newBranch, pid, err)
+	}
+
+	if oldBranch == "" || newBranch == "" {
+		return nil
+	}
+
+	oldProtectedBranch, err := getGitlabProtectedBranch(ctx, client, pid, oldBranch)
+	if err != nil && !is404(err) {
+		return fmt.Errorf("failed to get protection of branch %q for project %q: %w", oldBranch, pid, err)
+	}
+
+	// The protection is only copied to a branch created here, an existing branch is left as it is,
+	// e.g. because its protection is managed separately.
+	if created && oldProtectedBranch != nil {
+		_, _, err := client.ProtectedBranches.GetProtectedBranch(pid, newBranch, gitlab.WithContext(ctx))
+		if err != nil {
+			if !is404(err) {
+				return fmt.Errorf("failed to get protection of branch %q for project %q: %w", newBranch, pid, err)
+			}
+
+			log.Printf("[DEBUG] protect new default branch %q for project %q", newBranch, pid)
+			options := expandProtectRepositoryBranchesOptionsFrom(oldProtectedBranch)
+			options.Name = gitlab.String(newBranch)
+			if _, _, err := client.ProtectedBranches.ProtectRepositoryBranches(pid, options, gitlab.WithContext(ctx)); err != nil {
+				return fmt.Errorf("failed to protect default branch %q for project %q: %w", newBranch, pid, err)
+			}
+		}
+	}
+
+	if deleteOldBranch {
+		// protected branches can't be deleted
+		if oldProtectedBranch != nil {
+			log.Printf("[DEBUG] unprotect old default branch %q for project %q", oldBranch, pid)
+			if _, err := client.ProtectedBranches.UnprotectRepositoryBranches(pid, oldBranch, gitlab.WithContext(ctx)); err != nil {
+				return fmt.Errorf("failed to unprotect old default branch %q for project %q: %w", oldBranch, pid, err)
+			}
+		}
+
+		log.Printf("[DEBUG] delete old default branch %q for project %q", oldBranch, pid)
+		if _, err := client.Branches.DeleteBranch(pid, oldBranch, gitlab.WithContext(ctx)); err != nil && !is404(err) {
+			return fmt.Errorf("failed to delete old default branch %q for project %q: %w", oldBranch, pid, err)
+		}
+	}
+
+	return nil
+}
+
+// expandProtectRepositoryBranchesOptionsFrom builds the options to protect another branch with the same settings as the given protected branch.
+func expandProtectRepositoryBranchesOptionsFrom(protectedBranch *gitlabProtectedBranch) *gitlab.ProtectRepositoryBranchesOptions {
+	options := &gitlab.ProtectRepositoryBranchesOptions{
+		AllowForcePush:            gitlab.Bool(protectedBranch.AllowForcePush),
+		CodeOwnerApprovalRequired: gitlab.Bool(protectedBranch.CodeOwnerApprovalRequired),
+	}
+
+	options.PushAccessLevel, options.AllowedToPush = expandBranchAccessDescriptions(protectedBranch.PushAccessLevels)
+	options.MergeAccessLevel, options.AllowedToMerge = expandBranchAccessDescriptions(protectedBranch.MergeAccessLevels)
+	options.UnprotectAccessLevel, options.AllowedToUnprotect = expandBranchAccessDescriptions(protectedBranch.UnprotectAccessLevels)
+
+	return options
+}
+
+// expandBranchAccessDescriptions splits the access descriptions of a protected branch into the role based access level
+// and the additional user, group and deploy key permissions.
+func expandBranchAccessDescriptions(descriptions []*gitlabBranchAccessDescription) (*gitlab.AccessLevelValue, *[]*gitlab.BranchPermissionOptions) {
+	var accessLevel *gitlab.AccessLevelValue
+	var permissions []*gitlab.BranchPermissionOptions
+
+	for _, description := range descriptions {
+		switch {
+		case description.UserID != 0:
+			permissions = append(permissions, &gitlab.BranchPermissionOptions{UserID: gitlab.Int(description.UserID)})
+		case description.GroupID != 0:
+			permissions = append(permissions, &gitlab.BranchPermissionOptions{GroupID: gitlab.Int(description.GroupID)})
+		case description.DeployKeyID != 0:
+			permissions = append(permissions, &gitlab.BranchPermissionOptions{DeployKeyID: gitlab.Int(description.DeployKeyID)})
+		case accessLevel == nil:
+			accessLevel = gitlab.AccessLevel(description.AccessLevel)
+		default:
+			permissions = append(permissions, &gitlab.BranchPermissionOptions{AccessLevel: gitlab.AccessLevel(description.AccessLevel)})
+		}
+	}
+
+	if len(permissions) == 0 {
+		return accessLevel, nil
+	}
+	return accessLevel, &permissions
+}
+
+// resourceGitlabProjectCustomizeDiffTopics clears the topics if neither `topics` nor the deprecated `tags` are configured anymore.
+// Both are computed from the same topics, so that renaming `tags` to `topics` doesn't change the project.
+func resourceGitlabProjectCustomizeDiffTopics(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.GetAttr("tags").IsNull() || !config.GetAttr("topics").IsNull() {
+		return nil
+	}
+
+	for _, key := range []string{"tags", "topics"} {
+		if d.Get(key).(*schema.Set).Len() > 0 {
+			if err := d.SetNew(key, []interface{}{}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// expandEditProjectOptions returns the options to edit the project settings selected by include.
+func expandEditProjectOptions(d *schema.ResourceData, include func(string) bool) *gitlab.EditProjectOptions {
+	options := &gitlab.EditProjectOptions{}
+
+	if include("name") {
+		options.Name = gitlab.String(d.Get("name").(string))
+	}
+
+	if include("path") && (d.Get("path").(string) != "") {
+		options.Path = gitlab.String(d.Get("path").(string))
+	}
+
+	if include("description") {
+		options.Description = gitlab.String(d.Get("description").(string))
+	}
+
+	if include("visibility_level") {
 		options.Visibility = stringToVisibilityLevel(d.Get("visibility_level").(string))
 	}
 
-	if d.HasChange("merge_method") {
+	if include("merge_method") {
 		options.MergeMethod = stringToMergeMethod(d.Get("merge_method").(string))
 	}
 
-	if d.HasChange("only_allow_merge_if_pipeline_succeeds") {
+	if include("only_allow_merge_if_pipeline_succeeds") {
 		options.OnlyAllowMergeIfPipelineSucceeds = gitlab.Bool(d.Get("only_allow_merge_if_pipeline_succeeds").(bool))
 	}
 
-	if d.HasChange("only_allow_merge_if_all_discussions_are_resolved") {
+	if include("only_allow_merge_if_all_discussions_are_resolved") {
 		options.OnlyAllowMergeIfAllDiscussionsAreResolved = gitlab.Bool(d.Get("only_allow_merge_if_all_discussions_are_resolved").(bool))
 	}
 
-	if d.HasChange("allow_merge_on_skipped_pipeline") {
+	if include("allow_merge_on_skipped_pipeline") {
 		options.AllowMergeOnSkippedPipeline = gitlab.Bool(d.Get("allow_merge_on_skipped_pipeline").(bool))
 	}
 
-	if d.HasChange("request_access_enabled") {
+	if include("request_access_enabled") {
 		options.RequestAccessEnabled = gitlab.Bool(d.Get("request_access_enabled").(bool))
 	}
 
-	if d.HasChange("issues_enabled") {
+	if include("issues_enabled") {
 		options.IssuesEnabled = gitlab.Bool(d.Get("issues_enabled").(bool))
 	}
 
-	if d.HasChange("merge_requests_enabled") {
+	if include("merge_requests_enabled") {
 		options.MergeRequestsEnabled = gitlab.Bool(d.Get("merge_requests_enabled").(bool))
 	}
 
-	if d.HasChange("pipelines_enabled") {
+	if include("pipelines_enabled") {
 		options.JobsEnabled = gitlab.Bool(d.Get("pipelines_enabled").(bool))
 	}
 
-	if d.HasChange("approvals_before_merge") {
+	if include("approvals_before_merge") {
 		options.ApprovalsBeforeMerge = gitlab.Int(d.Get("approvals_before_merge").(int))
 	}
 
-	if d.HasChange("wiki_enabled") {
+	if include("wiki_enabled") {
 		options.WikiEnabled = gitlab.Bool(d.Get("wiki_enabled").(bool))
 	}
 
-	if d.HasChange("snippets_enabled") {
+	if include("snippets_enabled") {
 		options.SnippetsEnabled = gitlab.Bool(d.Get("snippets_enabled").(bool))
 	}
 
-	if d.HasChange("shared_runners_enabled") {
+	if include("shared_runners_enabled") {
 		options.SharedRunnersEnabled = gitlab.Bool(d.Get("shared_runners_enabled").(bool))
 	}
 
-	if d.HasChange("tags") {
+	if include("tags") {
 		options.TagList = stringSetToStringSlice(d.Get("tags").(*schema.Set))
 	}
 
-	if d.HasChange("container_registry_enabled") {
+	if include("topics") {
+		options.Topics = stringSetToStringSlice(d.Get("topics").(*schema.Set))
+	}
+
+	if include("container_registry_enabled") {
 		options.ContainerRegistryEnabled = gitlab.Bool(d.Get("container_registry_enabled").(bool))
 	}
 
-	if d.HasChange("lfs_enabled") {
+	if include("container_expiration_policy") {
+		options.ContainerExpirationPolicyAttributes = expandProjectContainerExpirationPolicyAttributes(d)
+	}
+
+	if include("lfs_enabled") {
 		options.LFSEnabled = gitlab.Bool(d.Get("lfs_enabled").(bool))
 	}
 
-	if d.HasChange("squash_option") {
+	if include("squash_option") {
 		options.SquashOption = stringToSquashOptionValue(d.Get("squash_option").(string))
 	}
 
-	if d.HasChange("remove_source_branch_after_merge") {
+	if include("remove_source_branch_after_merge") {
 		options.RemoveSourceBranchAfterMerge = gitlab.Bool(d.Get("remove_source_branch_after_merge").(bool))
 	}
 
-	if d.HasChange("packages_enabled") {
+	if include("packages_enabled") {
 		options.PackagesEnabled = gitlab.Bool(d.Get("packages_enabled").(bool))
 	}
 
-	if d.HasChange("pages_access_level") {
+	if include("pages_access_level") {
 		options.PagesAccessLevel = stringToAccessControlValue(d.Get("pages_access_level").(string))
 	}
 
-	if d.HasChange("mirror") {
-		options.ImportURL = gitlab.String(d.Get("import_url").(string))
+	if include("import_url_username") || include("import_url_password") {
+		options.ImportURL = gitlab.String(expandProjectImportURL(d))
+	}
+
+	if include("mirror") {
+		options.ImportURL = gitlab.String(expandProjectImportURL(d))
 		options.Mirror = gitlab.Bool(d.Get("mirror").(bool))
 	}
 
-	if d.HasChange("mirror_trigger_builds") {
-		options.ImportURL = gitlab.String(d.Get("import_url").(string))
+	if include("mirror_trigger_builds") {
+		options.ImportURL = gitlab.String(expandProjectImportURL(d))
 		options.MirrorTriggerBuilds = gitlab.Bool(d.Get("mirror_trigger_builds").(bool))
 	}
 
-	if d.HasChange("only_mirror_protected_branches") {
-		options.ImportURL = gitlab.String(d.Get("import_url").(string))
+	if include("only_mirror_protected_branches") {
+		options.ImportURL = gitlab.String(expandProjectImportURL(d))
 		options.OnlyMirrorProtectedBranches = gitlab.Bool(d.Get("only_mirror_protected_branches").(bool))
 	}
 
-	if d.HasChange("mirror_overwrites_diverged_branches") {
-		options.ImportURL = gitlab.String(d.Get("import_url").(string))
+	if include("mirror_overwrites_diverged_branches") {
+		options.ImportURL = gitlab.String(expandProjectImportURL(d))
 		options.MirrorOverwritesDivergedBranches = gitlab.Bool(d.Get("mirror_overwrites_diverged_branches").(bool))
 	}
 
-	if d.HasChange("build_coverage_regex") {
+	if include("build_coverage_regex") {
 		options.BuildCoverageRegex = gitlab.String(d.Get("build_coverage_regex").(string))
 	}
 
-	if d.HasChange("issues_template") {
+	if include("issues_template") {
 		options.IssuesTemplate = gitlab.String(d.Get("issues_template").(string))
 	}
 
-	if d.HasChange("merge_requests_template") {
+	if include("merge_requests_template") {
 		options.MergeRequestsTemplate = gitlab.String(d.Get("merge_requests_template").(string))
 	}
 
-	if d.HasChange("ci_config_path") {
+	if include("ci_config_path") {
 		options.CIConfigPath = gitlab.String(d.Get("ci_config_path").(string))
 	}
 
-	if *options != (gitlab.EditProjectOptions{}) {
-		log.Printf("[DEBUG] update gitlab project %s", d.Id())
-		_, _, err := client.Projects.EditProject(d.Id(), options, gitlab.WithContext(ctx))
-		if err != nil {
-			return diag.FromErr(err)
+	return options
+}
+
+// expandProjectForkEditOptions returns the options to apply the configured settings to a newly created fork.
+func expandProjectForkEditOptions(d *schema.ResourceData) *gitlab.EditProjectOptions {
+	options := expandEditProjectOptions(d, func(key string) bool {
+		switch key {
+		case "name", "path":
+			// the fork is created with them
+			return false
+		case "import_url_username", "import_url_password", "mirror", "mirror_trigger_builds", "only_mirror_protected_branches", "mirror_overwrites_diverged_branches",
+			"issues_template", "merge_requests_template", "container_expiration_policy":
+			// they're applied afterwards, like for any new project
+			return false
+		case "tags", "topics":
+			// only one of them may be configured, the topics of the upstream project are kept otherwise
+			return projectSettingConfigured(d)(key)
 		}
+		return true
+	})
+
+	if v, ok := d.GetOk("default_branch"); ok {
+		options.DefaultBranch = gitlab.String(v.(string))
 	}
 
-	if *transferOptions != (gitlab.TransferProjectOptions{}) {
-		log.Printf("[DEBUG] transferring project %s to namespace %d", d.Id(), transferOptions.Namespace)
-		_, _, err := client.Projects.TransferProject(d.Id(), transferOptions, gitlab.WithContext(ctx))
-		if err != nil {
-			return diag.FromErr(err)
-		}
+	return options
+}
+
+// expandProjectImportURL returns the import URL from the configuration,
+// with the credentials from import_url_username and import_url_password merged into it.
+func expandProjectImportURL(d *schema.ResourceData) string {
+	// The state only contains the import URL without credentials,
+	// so credentials that are embedded in the configured URL are taken from the configuration.
+	importURL := d.Get("import_url").(string)
+	if v := d.GetRawConfig().GetAttr("import_url"); v.IsKnown() && !v.IsNull() {
+		importURL = v.AsString()
 	}
 
-	if d.HasChange("archived") {
-		if d.Get("archived").(bool) {
-			if _, _, err := client.Projects.ArchiveProject(d.Id(), gitlab.WithContext(ctx)); err != nil {
-				return diag.Errorf("project %q could not be archived: %s", d.Id(), err)
-			}
-		} else {
-			if _, _, err := client.Projects.UnarchiveProject(d.Id(), gitlab.WithContext(ctx)); err != nil {
-				return diag.Errorf("project %q could not be unarchived: %s", d.Id(), err)
-			}
-		}
+	username := d.Get("import_url_username").(string)
+	password := d.Get("import_url_password").(string)
+	if username == "" && password == "" {
+		return importURL
 	}
 
-	if d.HasChange("push_rules") {
-		err := editOrAddPushRules(ctx, client, d.Id(), d)
-		var httpError *gitlab.ErrorResponse
-		if errors.As(err, &httpError) && httpError.Response.StatusCode == http.StatusNotFound {
-			log.Printf("[DEBUG] Failed to get push rules for project %q: %v", d.Id(), err)
-			return diag.Errorf("Project push rules are not supported in your version of GitLab")
-		}
-		if err != nil {
-			return diag.Errorf("Failed to edit push rules for project %q: %s", d.Id(), err)
-		}
+	u, err := url.Parse(importURL)
+	if err != nil {
+		return importURL
+	}
+	if password != "" {
+		u.User = url.UserPassword(username, password)
+	} else {
+		u.User = url.User(username)
 	}
+	return u.String()
+}
 
-	return resourceGitlabProjectRead(ctx, d, meta)
+// stripURLCredentials removes the credentials from the given URL.
+func stripURLCredentials(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.User == nil {
+		return rawURL
+	}
+	u.User = nil
+	return u.String()
 }
 
-func resourceGitlabProjectDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
-	log.Printf("[DEBUG] Delete gitlab project %s", d.Id())
+func getGitlabProject(ctx context.Context, client *gitlab.Client, pid string) (*gitlabProject, error) {
+	u := fmt.Sprintf("projects/%s", gitlab.PathEscape(pid))
 
-	_, err := client.Projects.DeleteProject(d.Id(), gitlab.WithContext(ctx))
+	req, err := client.NewRequest(http.MethodGet, u, nil, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
 	if err != nil {
-		return diag.FromErr(err)
+		return nil, err
 	}
 
-	// Wait for the project to be deleted.
-	// Deleting a project in gitlab is async.
-	stateConf := &resource.StateChangeConf{
-		Pending: []string{"Deleting"},
-		Target:  []string{"Deleted"},
-		Refresh: func() (interface{}, string, error) {
-			out, response, err := client.Projects.GetProject(d.Id(), nil, gitlab.WithContext(ctx))
+	project := new(gitlabProject)
+	if _, err := client.Do(req, project); err != nil {
+		return nil, err
+	}
+
+	return project, nil
+}
+
+// projectSettingConfigured reports whether a setting is set in the configuration,
+// including settings that are explicitly set to their zero value.
+func projectSettingConfigured(d *schema.ResourceData) func(string) bool {
+	return func(key string) bool {
+		return !d.GetRawConfig().GetAttr(key).IsNull()
+	}
+}
+
+// expandProjectSettingsOptions returns the options for the CI/CD and merge request settings selected by include,
+// together with the keys of the selected settings.
+func expandProjectSettingsOptions(d *schema.ResourceData, include func(string) bool) (*gitlabProjectSettingsOptions, []string) {
+	options := &gitlabProjectSettingsOptions{}
+	var keys []string
+
+	if include("build_timeout") {
+		options.BuildTimeout = gitlab.Int(d.Get("build_timeout").(int))
+		keys = append(keys, "build_timeout")
+	}
+
+	if include("ci_default_git_depth") {
+		options.CIDefaultGitDepth = gitlab.Int(d.Get("ci_default_git_depth").(int))
+		keys = append(keys, "ci_default_git_depth")
+	}
+
+	if include("auto_cancel_pending_pipelines") {
+		options.AutoCancelPendingPipelines = gitlab.String(d.Get("auto_cancel_pending_pipelines").(string))
+		keys = append(keys, "auto_cancel_pending_pipelines")
+	}
+
+	if include("ci_forward_deployment_enabled") {
+		options.CIForwardDeploymentEnabled = gitlab.Bool(d.Get("ci_forward_deployment_enabled").(bool))
+		keys = append(keys, "ci_forward_deployment_enabled")
+	}
+
+	if include("keep_latest_artifact") {
+		options.KeepLatestArtifact = gitlab.Bool(d.Get("keep_latest_artifact").(bool))
+		keys = append(keys, "keep_latest_artifact")
+	}
+
+	if include("auto_devops_enabled") {
+		options.AutoDevopsEnabled = gitlab.Bool(d.Get("auto_devops_enabled").(bool))
+		keys = append(keys, "auto_devops_enabled")
+	}
+
+	if include("auto_devops_deploy_strategy") {
+		options.AutoDevopsDeployStrategy = gitlab.String(d.Get("auto_devops_deploy_strategy").(string))
+		keys = append(keys, "auto_devops_deploy_strategy")
+	}
+
+	if include("public_builds") {
+		options.PublicBuilds = gitlab.Bool(d.Get("public_builds").(bool))
+		keys = append(keys, "public_builds")
+	}
+
+	if include("restrict_user_defined_variables") {
+		options.RestrictUserDefinedVariables = gitlab.Bool(d.Get("restrict_user_defined_variables").(bool))
+		keys = append(keys, "restrict_user_defined_variables")
+	}
+
+	if include("merge_pipelines_enabled") {
+		options.MergePipelinesEnabled = gitlab.Bool(d.Get("merge_pipelines_enabled").(bool))
+		keys = append(keys, "merge_pipelines_enabled")
+	}
+
+	if include("merge_trains_enabled") {
+		options.MergeTrainsEnabled = gitlab.Bool(d.Get("merge_trains_enabled").(bool))
+		keys = append(keys, "merge_trains_enabled")
+	}
+
+	if include("merge_commit_template") {
+		options.MergeCommitTemplate = gitlab.String(d.Get("merge_commit_template").(string))
+		keys = append(keys, "merge_commit_template")
+	}
+
+	if include("squash_commit_template") {
+		options.SquashCommitTemplate = gitlab.String(d.Get("squash_commit_template").(string))
+		keys = append(keys, "squash_commit_template")
+	}
+
+	if include("suggestion_commit_message") {
+		options.SuggestionCommitMessage = gitlab.String(d.Get("suggestion_commit_message").(string))
+		keys = append(keys, "suggestion_commit_message")
+	}
+
+	if include("resolve_outdated_diff_discussions") {
+		options.ResolveOutdatedDiffDiscussions = gitlab.Bool(d.Get("resolve_outdated_diff_discussions").(bool))
+		keys = append(keys, "resolve_outdated_diff_discussions")
+	}
+
+	if include("printing_merge_request_link_enabled") {
+		options.PrintingMergeRequestLinkEnabled = gitlab.Bool(d.Get("printing_merge_request_link_enabled").(bool))
+		keys = append(keys, "printing_merge_request_link_enabled")
+	}
+
+	if include("autoclose_referenced_issues") {
+		options.AutocloseReferencedIssues = gitlab.Bool(d.Get("autoclose_referenced_issues").(bool))
+		keys = append(keys, "autoclose_referenced_issues")
+	}
+
+	return options, keys
+}
+
+// editGitlabProjectSettings updates the CI/CD and merge request settings of a project,
+// after checking that the GitLab instance supports all of the given settings.
+func editGitlabProjectSettings(ctx context.Context, client *gitlab.Client, pid string, options *gitlabProjectSettingsOptions, keys []string) error {
+	// the version is only requested once, and only if any of the settings has requirements
+	var version *gitlabVersion
+	for _, key := range keys {
+		requirement, ok := projectSettingsRequirements[key]
+		if !ok {
+			continue
+		}
+
+		if version == nil {
+			var err error
+			if version, err = getGitlabVersion(ctx, client); err != nil {
+				return fmt.Errorf("failed to check GitLab version: %w", err)
+			}
+		}
+
+		if requirement.minVersion != "" {
+			isSupported, err := version.isAtLeast(requirement.minVersion)
 			if err != nil {
-				if response.StatusCode == 404 {
-					return out, "Deleted", nil
-				}
-				log.Printf("[ERROR] Received error: %#v", err)
-				return out, "Error", err
+				return err
 			}
-			if out.MarkedForDeletionAt != nil {
-				// Represents a Gitlab EE soft-delete
-				return out, "Deleted", nil
+			if !isSupported {
+				return fmt.Errorf("%q requires GitLab %s or later", key, requirement.minVersion)
 			}
-			return out, "Deleting", nil
-		},
+		}
 
-		Timeout:    10 * time.Minute,
-		MinTimeout: 3 * time.Second,
-		Delay:      5 * time.Second,
+		if requirement.enterprise && version.isCommunityEdition() {
+			return fmt.Errorf("%q requires GitLab Enterprise Edition", key)
+		}
 	}
 
-	_, err = stateConf.WaitForStateContext(ctx)
+	u := fmt.Sprintf("projects/%s", gitlab.PathEscape(pid))
+
+	req, err := client.NewRequest(http.MethodPut, u, options, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
 	if err != nil {
-		return diag.Errorf("error waiting for project (%s) to become deleted: %s", d.Id(), err)
+		return err
 	}
-	return nil
+
+	_, err = client.Do(req, nil)
+	return err
 }
 
 func editOrAddPushRules(ctx context.Context, client *gitlab.Client, projectID string, d *schema.ResourceData) error {
@@ -1025,6 +1836,49 @@ func expandAddProjectPushRuleOptions(d *schema.ResourceData) *gitlab.AddProjectP
 	return options
 }
 
+func expandProjectContainerExpirationPolicyAttributes(d *schema.ResourceData) *gitlab.ContainerExpirationPolicyAttributes {
+	policy := &gitlab.ContainerExpirationPolicyAttributes{
+		Enabled:         gitlab.Bool(d.Get("container_expiration_policy.0.enabled").(bool)),
+		NameRegexDelete: gitlab.String(d.Get("container_expiration_policy.0.name_regex_delete").(string)),
+		NameRegexKeep:   gitlab.String(d.Get("container_expiration_policy.0.name_regex_keep").(string)),
+	}
+
+	if v, ok := d.GetOk("container_expiration_policy.0.cadence"); ok {
+		policy.Cadence = gitlab.String(v.(string))
+	}
+
+	if v, ok := d.GetOk("container_expiration_policy.0.keep_n"); ok {
+		policy.KeepN = gitlab.Int(v.(int))
+	}
+
+	if v, ok := d.GetOk("container_expiration_policy.0.older_than"); ok {
+		policy.OlderThan = gitlab.String(v.(string))
+	}
+
+	return policy
+}
+
+func flattenProjectContainerExpirationPolicy(policy *gitlab.ContainerExpirationPolicy) []map[string]interface{} {
+	if policy == nil {
+		return []map[string]interface{}{}
+	}
+
+	values := map[string]interface{}{
+		"enabled":           policy.Enabled,
+		"cadence":           policy.Cadence,
+		"keep_n":            policy.KeepN,
+		"older_than":        policy.OlderThan,
+		"name_regex_delete": policy.NameRegexDelete,
+		"name_regex_keep":   policy.NameRegexKeep,
+		"next_run_at":       "",
+	}
+	if policy.NextRunAt != nil {
+		values["next_run_at"] = policy.NextRunAt.Format(time.RFC3339)
+	}
+
+	return []map[string]interface{}{values}
+}
+
 func flattenProjectPushRules(pushRules *gitlab.ProjectPushRules) (values []map[string]interface{}) {
 	if pushRules == nil {
 		return []map[string]interface{}{}
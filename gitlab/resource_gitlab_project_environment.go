@@ -0,0 +1,280 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+var environmentTiers = []string{"production", "staging", "testing", "development", "other"}
+
+// gitlabEnvironment extends the environment of go-gitlab with the deployment tier.
+type gitlabEnvironment struct {
+	gitlab.Environment
+	Tier string `json:"tier"`
+}
+
+// gitlabEnvironmentOptions extends the create and edit environment options of go-gitlab with the deployment tier.
+type gitlabEnvironmentOptions struct {
+	Name        *string `json:"name,omitempty"`
+	ExternalURL *string `json:"external_url,omitempty"`
+	Tier        *string `json:"tier,omitempty"`
+}
+
+func resourceGitlabProjectEnvironment() *schema.Resource {
+	return &schema.Resource{
+		Description: "This resource allows you to create and manage an environment of a project.\n\n" +
+			"The `name` of the environment can be used as `environment_scope` of `gitlab_project_variable` and the cluster resources. " +
+			"GitLab only deletes stopped environments, so set `stop_before_destroy` to stop an available environment before it's deleted.",
+
+		CreateContext: resourceGitlabProjectEnvironmentCreate,
+		ReadContext:   resourceGitlabProjectEnvironmentRead,
+		UpdateContext: resourceGitlabProjectEnvironmentUpdate,
+		DeleteContext: resourceGitlabProjectEnvironmentDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Description:  "The ID or full path of the project.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"name": {
+				Description:  "The name of the environment, e.g. `production` or `review/my-feature`.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"external_url": {
+				Description:  "Place to link to for this environment.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+			},
+			"tier": {
+				Description:  "The deployment tier of the environment. Valid values are `production`, `staging`, `testing`, `development` and `other`. Defaults to the tier GitLab derives from the name.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice(environmentTiers, false),
+			},
+			"stop_before_destroy": {
+				Description: "Stop the environment before destroying it. Available environments can't be deleted otherwise.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"environment_id": {
+				Description: "The ID of the environment.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"slug": {
+				Description: "The name of the environment in lowercase, shortened to 63 bytes.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"state": {
+				Description: "The state of the environment, e.g. `available` or `stopped`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceGitlabProjectEnvironmentCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	project := d.Get("project").(string)
+
+	options := &gitlabEnvironmentOptions{
+		Name: gitlab.String(d.Get("name").(string)),
+	}
+	if v, ok := d.GetOk("external_url"); ok {
+		options.ExternalURL = gitlab.String(v.(string))
+	}
+	if v, ok := d.GetOk("tier"); ok {
+		options.Tier = gitlab.String(v.(string))
+	}
+
+	log.Printf("[DEBUG] create gitlab environment %q for project %s", *options.Name, project)
+
+	u := fmt.Sprintf("projects/%s/environments", gitlab.PathEscape(project))
+	environment, err := doGitlabEnvironmentRequest(ctx, client, http.MethodPost, u, options)
+	if err != nil {
+		return diag.Errorf("error creating environment %q on project %q: %v", *options.Name, project, err)
+	}
+
+	environmentID := strconv.Itoa(environment.ID)
+	d.SetId(buildTwoPartID(&project, &environmentID))
+
+	return resourceGitlabProjectEnvironmentRead(ctx, d, meta)
+}
+
+func resourceGitlabProjectEnvironmentRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	project, environmentID, err := projectAndEnvironmentIDFromID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] read gitlab environment %d for project %s", environmentID, project)
+
+	environment, err := getGitlabEnvironment(ctx, client, project, environmentID)
+	if err != nil {
+		if is404(err) {
+			log.Printf("[DEBUG] gitlab environment %d for project %s not found, removing from state", environmentID, project)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	d.Set("project", project)
+	d.Set("name", environment.Name)
+	d.Set("external_url", environment.ExternalURL)
+	d.Set("tier", environment.Tier)
+	d.Set("environment_id", environment.ID)
+	d.Set("slug", environment.Slug)
+	d.Set("state", environment.State)
+
+	return nil
+}
+
+func resourceGitlabProjectEnvironmentUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	project, environmentID, err := projectAndEnvironmentIDFromID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	options := &gitlabEnvironmentOptions{}
+	if d.HasChange("external_url") {
+		options.ExternalURL = gitlab.String(d.Get("external_url").(string))
+	}
+	if d.HasChange("tier") {
+		options.Tier = gitlab.String(d.Get("tier").(string))
+	}
+
+	// stop_before_destroy is only used by Terraform
+	if *options == (gitlabEnvironmentOptions{}) {
+		return resourceGitlabProjectEnvironmentRead(ctx, d, meta)
+	}
+
+	log.Printf("[DEBUG] update gitlab environment %d for project %s", environmentID, project)
+
+	u := fmt.Sprintf("projects/%s/environments/%d", gitlab.PathEscape(project), environmentID)
+	if _, err := doGitlabEnvironmentRequest(ctx, client, http.MethodPut, u, options); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceGitlabProjectEnvironmentRead(ctx, d, meta)
+}
+
+func resourceGitlabProjectEnvironmentDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	project, environmentID, err := projectAndEnvironmentIDFromID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.Get("stop_before_destroy").(bool) {
+		if err := stopGitlabEnvironment(ctx, client, project, environmentID); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	log.Printf("[DEBUG] delete gitlab environment %d for project %s", environmentID, project)
+
+	if _, err := client.Environments.DeleteEnvironment(project, environmentID, gitlab.WithContext(ctx)); err != nil {
+		return diag.Errorf("failed to delete environment %d of project %s, it may have to be stopped first by setting stop_before_destroy: %v", environmentID, project, err)
+	}
+
+	return nil
+}
+
+// stopGitlabEnvironment stops the environment and waits until its stop actions have finished.
+func stopGitlabEnvironment(ctx context.Context, client *gitlab.Client, project string, environmentID int) error {
+	environment, err := getGitlabEnvironment(ctx, client, project, environmentID)
+	if err != nil {
+		return err
+	}
+	if environment.State == "stopped" {
+		return nil
+	}
+
+	log.Printf("[DEBUG] stop gitlab environment %d for project %s", environmentID, project)
+
+	if _, err := client.Environments.StopEnvironment(project, environmentID, gitlab.WithContext(ctx)); err != nil {
+		return fmt.Errorf("failed to stop environment %d of project %s: %v", environmentID, project, err)
+	}
+
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"available", "stopping"},
+		Target:     []string{"stopped"},
+		Timeout:    5 * time.Minute,
+		MinTimeout: 3 * time.Second,
+		Refresh: func() (interface{}, string, error) {
+			environment, err := getGitlabEnvironment(ctx, client, project, environmentID)
+			if err != nil {
+				return nil, "", err
+			}
+
+			return environment, environment.State, nil
+		},
+	}
+
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("error while waiting for environment %d of project %s to stop: %v", environmentID, project, err)
+	}
+
+	return nil
+}
+
+func projectAndEnvironmentIDFromID(id string) (string, int, error) {
+	project, environment, err := parseTwoPartID(id)
+	if err != nil {
+		return "", 0, err
+	}
+
+	environmentID, err := strconv.Atoi(environment)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid environment ID %q: %v", environment, err)
+	}
+
+	return project, environmentID, nil
+}
+
+func getGitlabEnvironment(ctx context.Context, client *gitlab.Client, project string, environmentID int) (*gitlabEnvironment, error) {
+	// go-gitlab doesn't expose the tier of environments
+	u := fmt.Sprintf("projects/%s/environments/%d", gitlab.PathEscape(project), environmentID)
+	return doGitlabEnvironmentRequest(ctx, client, http.MethodGet, u, nil)
+}
+
+func doGitlabEnvironmentRequest(ctx context.Context, client *gitlab.Client, method string, u string, options interface{}) (*gitlabEnvironment, error) {
+	req, err := client.NewRequest(method, u, options, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+
+	environment := new(gitlabEnvironment)
+	if _, err := client.Do(req, environment); err != nil {
+		return nil, err
+	}
+
+	return environment, nil
+}
@@ -0,0 +1,104 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/xanzy/go-gitlab"
+)
+
+func TestAccGitlabProjectEnvironment_basic(t *testing.T) {
+	testAccCheck(t)
+
+	client := testAccNewClient(t)
+	project := testAccCreateProject(t, client)
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabProjectEnvironmentDestroy(client),
+		Steps: []resource.TestStep{
+			// Create an environment and scope a variable to it
+			{
+				Config: fmt.Sprintf(`
+resource "gitlab_project_environment" "this" {
+  project             = %d
+  name                = "staging"
+  external_url        = "https://staging.example.com"
+  stop_before_destroy = true
+}
+
+resource "gitlab_project_variable" "this" {
+  project           = %d
+  key               = "DEPLOY_TARGET"
+  value             = "staging"
+  environment_scope = gitlab_project_environment.this.name
+}
+`, project.ID, project.ID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlab_project_environment.this", "state", "available"),
+					resource.TestCheckResourceAttr("gitlab_project_environment.this", "tier", "staging"),
+					resource.TestCheckResourceAttrSet("gitlab_project_environment.this", "environment_id"),
+					resource.TestCheckResourceAttrSet("gitlab_project_environment.this", "slug"),
+					resource.TestCheckResourceAttr("gitlab_project_variable.this", "environment_scope", "staging"),
+				),
+			},
+			// Verify import
+			{
+				ResourceName:            "gitlab_project_environment.this",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"stop_before_destroy"},
+			},
+			// Update the external URL and the tier
+			{
+				Config: fmt.Sprintf(`
+resource "gitlab_project_environment" "this" {
+  project             = %d
+  name                = "staging"
+  external_url        = "https://staging.example.org"
+  tier                = "testing"
+  stop_before_destroy = true
+}
+`, project.ID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlab_project_environment.this", "external_url", "https://staging.example.org"),
+					resource.TestCheckResourceAttr("gitlab_project_environment.this", "tier", "testing"),
+				),
+			},
+			// Verify import
+			{
+				ResourceName:            "gitlab_project_environment.this",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"stop_before_destroy"},
+			},
+		},
+	})
+}
+
+func testAccCheckGitlabProjectEnvironmentDestroy(client *gitlab.Client) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != "gitlab_project_environment" {
+				continue
+			}
+
+			project, environmentID, err := projectAndEnvironmentIDFromID(rs.Primary.ID)
+			if err != nil {
+				return err
+			}
+
+			_, err = getGitlabEnvironment(context.Background(), client, project, environmentID)
+			if err == nil {
+				return fmt.Errorf("environment %s still exists", rs.Primary.ID)
+			}
+			if !is404(err) {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,155 @@
+package gitlab
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func resourceGitlabProjectExport() *schema.Resource {
+	return &schema.Resource{
+		Description: "This resource allows you to export a project and download the export archive to a local file, e.g. as a backup before risky changes.\n\n" +
+			"A new export is created when the local file is missing or was modified, or when the `triggers` change. " +
+			"Destroying the resource doesn't delete the local file.",
+
+		CreateContext: resourceGitlabProjectExportCreate,
+		ReadContext:   resourceGitlabProjectExportRead,
+		DeleteContext: resourceGitlabProjectExportDelete,
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Description:  "The ID or full path of the project to export.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"path": {
+				Description:  "The local path to download the export archive to. Missing parent directories are created.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"triggers": {
+				Description: "Arbitrary map of values that, when changed, will trigger a new export.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"checksum": {
+				Description: "The hex encoded SHA256 checksum of the downloaded export archive.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"size": {
+				Description: "The size of the downloaded export archive in bytes.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceGitlabProjectExportCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	project := d.Get("project").(string)
+	path := d.Get("path").(string)
+
+	log.Printf("[DEBUG] schedule export of gitlab project %s", project)
+
+	if _, err := client.ProjectImportExport.ScheduleExport(project, nil, gitlab.WithContext(ctx)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] waiting for project %s export to finish", project)
+
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"none", "queued", "started", "regeneration_in_progress"},
+		Target:  []string{"finished"},
+		Timeout: 10 * time.Minute,
+		// The export is scheduled asynchronously, so the status of a previous export may still be reported right away.
+		Delay:      5 * time.Second,
+		MinTimeout: 3 * time.Second,
+		Refresh: func() (interface{}, string, error) {
+			status, _, err := client.ProjectImportExport.ExportStatus(project, gitlab.WithContext(ctx))
+			if err != nil {
+				return nil, "", err
+			}
+
+			return status, status.ExportStatus, nil
+		},
+	}
+
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return diag.Errorf("error while waiting for project %s export to finish: %s", project, err)
+	}
+
+	log.Printf("[DEBUG] download export of gitlab project %s to %q", project, path)
+
+	content, _, err := client.ProjectImportExport.ExportDownload(project, gitlab.WithContext(ctx))
+	if err != nil {
+		return diag.Errorf("failed to download export of project %s: %s", project, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return diag.Errorf("failed to create directory for project export archive %q: %s", path, err)
+	}
+	if err := ioutil.WriteFile(path, content, 0o600); err != nil {
+		return diag.Errorf("failed to write project export archive %q: %s", path, err)
+	}
+
+	d.SetId(buildTwoPartID(&project, &path))
+
+	return resourceGitlabProjectExportRead(ctx, d, meta)
+}
+
+func resourceGitlabProjectExportRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	path := d.Get("path").(string)
+	log.Printf("[DEBUG] read project export archive %q", path)
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.Printf("[DEBUG] project export archive %q not found, removing from state", path)
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("failed to read project export archive %q: %s", path, err)
+	}
+
+	checksum := projectExportChecksum(content)
+	if old := d.Get("checksum").(string); old != "" && old != checksum {
+		log.Printf("[DEBUG] project export archive %q was modified, removing from state", path)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("checksum", checksum)
+	d.Set("size", len(content))
+
+	return nil
+}
+
+func resourceGitlabProjectExportDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// The export archive is a backup, so it's intentionally kept.
+	log.Printf("[DEBUG] keeping project export archive %q", d.Get("path").(string))
+	return nil
+}
+
+func projectExportChecksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
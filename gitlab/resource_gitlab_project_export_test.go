@@ -0,0 +1,75 @@
+package gitlab
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccGitlabProjectExport_basic(t *testing.T) {
+	testAccCheck(t)
+
+	client := testAccNewClient(t)
+	project := testAccCreateProject(t, client)
+	archive := filepath.Join(t.TempDir(), "backups", "export.tar.gz")
+
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGitlabProjectExportConfig(project.ID, archive, "1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabProjectExportArchive("gitlab_project_export.this", archive),
+				),
+			},
+			// Changing the triggers creates a new export
+			{
+				Config: testAccGitlabProjectExportConfig(project.ID, archive, "2"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabProjectExportArchive("gitlab_project_export.this", archive),
+					resource.TestCheckResourceAttr("gitlab_project_export.this", "triggers.run", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckGitlabProjectExportArchive(n, archive string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("not found: %s", n)
+		}
+
+		content, err := ioutil.ReadFile(archive)
+		if err != nil {
+			return fmt.Errorf("failed to read project export archive: %w", err)
+		}
+		if len(content) == 0 {
+			return fmt.Errorf("project export archive %q is empty", archive)
+		}
+
+		actual := projectExportChecksum(content)
+		if rs.Primary.Attributes["checksum"] != actual {
+			return fmt.Errorf("checksum is %q; want %q", rs.Primary.Attributes["checksum"], actual)
+		}
+		return nil
+	}
+}
+
+func testAccGitlabProjectExportConfig(projectID int, archive, run string) string {
+	return fmt.Sprintf(`
+resource "gitlab_project_export" "this" {
+  project = %d
+  path    = %q
+
+  triggers = {
+    run = %q
+  }
+}
+	`, projectID, archive, run)
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -23,6 +24,7 @@ func resourceGitlabProjectFreezePeriod() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		CustomizeDiff: customizeDiffCronChange([]string{"freeze_start", "freeze_end", "cron_timezone"}, "next_freeze_start", "next_freeze_end"),
 		Schema: map[string]*schema.Schema{
 			"project_id": {
 				Description: "The id of the project to add the schedule to.",
@@ -31,20 +33,33 @@ func resourceGitlabProjectFreezePeriod() *schema.Resource {
 				ForceNew:    true,
 			},
 			"freeze_start": {
-				Description: "Start of the Freeze Period in cron format (e.g. `0 1 * * *`).",
-				Type:        schema.TypeString,
-				Required:    true,
+				Description:      "Start of the Freeze Period in cron format (e.g. `0 1 * * *`).",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateCronExpression,
 			},
 			"freeze_end": {
-				Description: "End of the Freeze Period in cron format (e.g. `0 2 * * *`).",
-				Type:        schema.TypeString,
-				Required:    true,
+				Description:      "End of the Freeze Period in cron format (e.g. `0 2 * * *`).",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateCronExpression,
 			},
 			"cron_timezone": {
-				Description: "The timezone.",
+				Description:      "The timezone, either of the tz database (e.g. `Europe/Berlin`) or a Rails time zone name (e.g. `Berlin`).",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "UTC",
+				ValidateDiagFunc: validateCronTimezone,
+			},
+			"next_freeze_start": {
+				Description: "The start of the active freeze window, or else of the next one, in RFC3339 format, computed when the resource is read. Like in GitLab, a freeze window is active if `freeze_start` matched more recently than `freeze_end`.",
 				Type:        schema.TypeString,
-				Optional:    true,
-				Default:     "UTC",
+				Computed:    true,
+			},
+			"next_freeze_end": {
+				Description: "The end of the active freeze window, or else of the next one, in RFC3339 format, i.e. the first time `freeze_end` matches after `next_freeze_start`.",
+				Type:        schema.TypeString,
+				Computed:    true,
 			},
 		},
 	}
@@ -97,6 +112,15 @@ func resourceGitlabProjectFreezePeriodRead(ctx context.Context, d *schema.Resour
 	d.Set("cron_timezone", freezePeriod.CronTimezone)
 	d.Set("project_id", projectID)
 
+	nextFreezeStart, nextFreezeEnd := "", ""
+	if start, end, err := currentOrNextCronWindow(freezePeriod.FreezeStart, freezePeriod.FreezeEnd, freezePeriod.CronTimezone, time.Now()); err == nil {
+		nextFreezeStart, nextFreezeEnd = start.Format(time.RFC3339), end.Format(time.RFC3339)
+	} else {
+		log.Printf("[WARN] failed to compute the next window of gitlab FreezePeriod %s: %v", d.Id(), err)
+	}
+	d.Set("next_freeze_start", nextFreezeStart)
+	d.Set("next_freeze_end", nextFreezeEnd)
+
 	return nil
 }
 
@@ -2,6 +2,7 @@ package gitlab
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
@@ -29,8 +30,16 @@ func TestAccGitlabProjectFreezePeriod_basic(t *testing.T) {
 						FreezeEnd:    "0 7 * * 1",
 						CronTimezone: "UTC",
 					}),
+					resource.TestCheckResourceAttrSet("gitlab_project_freeze_period.schedule", "next_freeze_start"),
+					resource.TestCheckResourceAttrSet("gitlab_project_freeze_period.schedule", "next_freeze_end"),
 				),
 			},
+			// Invalid cron expressions and time zones fail at plan time
+			{
+				Config:      testAccGitlabProjectFreezePeriodInvalidConfig(rInt),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid (cron expression|time zone)`),
+			},
 			// Update the freeze period to change the parameters
 			{
 				Config: testAccGitlabProjectFreezePeriodUpdateConfig(rInt),
@@ -166,3 +175,23 @@ resource "gitlab_project_freeze_period" "schedule" {
 }
 	`, rInt)
 }
+
+func testAccGitlabProjectFreezePeriodInvalidConfig(rInt int) string {
+	return fmt.Sprintf(`
+resource "gitlab_project" "foo" {
+  name        = "foo-%d"
+  description = "Terraform acceptance tests"
+
+  # So that acceptance tests can be run in a gitlab organization
+  # with no billing
+  visibility_level = "public"
+}
+
+resource "gitlab_project_freeze_period" "schedule" {
+  project_id    = gitlab_project.foo.id
+  freeze_start  = "0 25 * * 5"
+  freeze_end    = "0 7 * * 1"
+  cron_timezone = "Europe/Atlantis"
+}
+	`, rInt)
+}
@@ -0,0 +1,263 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func resourceGitlabProjectImport() *schema.Resource {
+	return &schema.Resource{
+		Description: "This resource allows you to create a project by importing a local project export archive, e.g. one exported from another GitLab instance.\n\n" +
+			"The project is deleted when the resource is destroyed. Any change to the arguments re-imports the project.",
+
+		CreateContext: resourceGitlabProjectImportCreate,
+		ReadContext:   resourceGitlabProjectImportRead,
+		DeleteContext: resourceGitlabProjectImportDelete,
+
+		Schema: map[string]*schema.Schema{
+			"file": {
+				Description:  "The path to the local `.tar.gz` project export archive to import.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"path": {
+				Description:  "The path of the imported project.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"name": {
+				Description: "The name of the imported project. Defaults to the path of the project.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+			},
+			"namespace": {
+				Description: "The ID or path of the namespace to import the project into. Defaults to the namespace of the current user.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"overwrite": {
+				Description: "If there is a project with the same path, the import overwrites it.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				ForceNew:    true,
+			},
+			"override_params": {
+				Description: "Project settings that override the settings contained in the export archive.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"description": {
+							Description: "A description of the project.",
+							Type:        schema.TypeString,
+							Optional:    true,
+							ForceNew:    true,
+						},
+						"visibility_level": {
+							Description:  "The visibility of the project. Valid values are `private`, `internal` and `public`.",
+							Type:         schema.TypeString,
+							Optional:     true,
+							ForceNew:     true,
+							ValidateFunc: validation.StringInSlice([]string{"private", "internal", "public"}, true),
+						},
+						"default_branch": {
+							Description: "The default branch of the project.",
+							Type:        schema.TypeString,
+							Optional:    true,
+							ForceNew:    true,
+						},
+						"ci_config_path": {
+							Description: "Custom path to the CI config file.",
+							Type:        schema.TypeString,
+							Optional:    true,
+							ForceNew:    true,
+						},
+					},
+				},
+			},
+			"project_id": {
+				Description: "The ID of the imported project.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"path_with_namespace": {
+				Description: "The path of the imported project with namespace.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"web_url": {
+				Description: "The URL to visit the imported project.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"import_status": {
+				Description: "The status of the import.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// gitlabProjectImportStatus extends the import status of go-gitlab with the import error.
+type gitlabProjectImportStatus struct {
+	gitlab.ImportStatus
+	ImportError string `json:"import_error"`
+}
+
+func resourceGitlabProjectImportCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+
+	options := &gitlab.ImportFileOptions{
+		Path:      gitlab.String(d.Get("path").(string)),
+		Overwrite: gitlab.Bool(d.Get("overwrite").(bool)),
+	}
+
+	if v, ok := d.GetOk("name"); ok {
+		options.Name = gitlab.String(v.(string))
+	}
+
+	if v, ok := d.GetOk("namespace"); ok {
+		options.Namespace = gitlab.String(v.(string))
+	}
+
+	if _, ok := d.GetOk("override_params"); ok {
+		options.OverrideParams = expandProjectImportOverrideParams(d)
+	}
+
+	file := d.Get("file").(string)
+	archive, err := os.Open(file)
+	if err != nil {
+		return diag.Errorf("failed to open project export archive %q: %s", file, err)
+	}
+	defer archive.Close()
+
+	log.Printf("[DEBUG] import gitlab project %q from %q", *options.Path, file)
+
+	status, _, err := client.ProjectImportExport.ImportFromFile(archive, options, gitlab.WithContext(ctx))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// from this point onwards no matter how we return, resource creation
+	// is committed to state since we set its ID
+	d.SetId(fmt.Sprintf("%d", status.ID))
+
+	log.Printf("[DEBUG] waiting for project %q import to finish", d.Id())
+
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"none", "scheduled", "started"},
+		Target:  []string{"finished"},
+		Timeout: 10 * time.Minute,
+		Refresh: func() (interface{}, string, error) {
+			status, err := getGitlabProjectImportStatus(ctx, client, d.Id())
+			if err != nil {
+				return nil, "", err
+			}
+
+			if status.ImportStatus.ImportStatus == "failed" {
+				return status, status.ImportStatus.ImportStatus, fmt.Errorf("import failed: %s", status.ImportError)
+			}
+
+			return status, status.ImportStatus.ImportStatus, nil
+		},
+	}
+
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return diag.Errorf("error while waiting for project %q import to finish: %s", d.Id(), err)
+	}
+
+	return resourceGitlabProjectImportRead(ctx, d, meta)
+}
+
+func resourceGitlabProjectImportRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	log.Printf("[DEBUG] read imported gitlab project %s", d.Id())
+
+	project, _, err := client.Projects.GetProject(d.Id(), nil, gitlab.WithContext(ctx))
+	if err != nil {
+		if is404(err) {
+			log.Printf("[DEBUG] imported gitlab project %s not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	if project.MarkedForDeletionAt != nil {
+		log.Printf("[DEBUG] imported gitlab project %s is marked for deletion", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("project_id", project.ID)
+	d.Set("name", project.Name)
+	d.Set("path", project.Path)
+	d.Set("path_with_namespace", project.PathWithNamespace)
+	d.Set("web_url", project.WebURL)
+	d.Set("import_status", project.ImportStatus)
+
+	return nil
+}
+
+func resourceGitlabProjectImportDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return resourceGitlabProjectDelete(ctx, d, meta)
+}
+
+func getGitlabProjectImportStatus(ctx context.Context, client *gitlab.Client, project string) (*gitlabProjectImportStatus, error) {
+	// go-gitlab doesn't expose the import error of the import status
+	u := fmt.Sprintf("projects/%s/import", gitlab.PathEscape(project))
+
+	req, err := client.NewRequest(http.MethodGet, u, nil, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+
+	status := new(gitlabProjectImportStatus)
+	if _, err := client.Do(req, status); err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+func expandProjectImportOverrideParams(d *schema.ResourceData) *gitlab.CreateProjectOptions {
+	options := &gitlab.CreateProjectOptions{}
+
+	if v, ok := d.GetOk("override_params.0.description"); ok {
+		options.Description = gitlab.String(v.(string))
+	}
+
+	if v, ok := d.GetOk("override_params.0.visibility_level"); ok {
+		options.Visibility = stringToVisibilityLevel(v.(string))
+	}
+
+	if v, ok := d.GetOk("override_params.0.default_branch"); ok {
+		options.DefaultBranch = gitlab.String(v.(string))
+	}
+
+	if v, ok := d.GetOk("override_params.0.ci_config_path"); ok {
+		options.CIConfigPath = gitlab.String(v.(string))
+	}
+
+	return options
+}
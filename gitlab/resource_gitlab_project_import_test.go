@@ -0,0 +1,101 @@
+package gitlab
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func TestAccGitlabProjectImport_basic(t *testing.T) {
+	testAccCheck(t)
+
+	client := testAccNewClient(t)
+	project := testAccCreateProject(t, client)
+	archive := testAccExportProject(t, client, project)
+	path := acctest.RandomWithPrefix("acctest-import")
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabProjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "gitlab_project_import" "this" {
+  file = %q
+  path = %q
+
+  override_params {
+    description      = "An imported project"
+    visibility_level = "public"
+  }
+}
+				`, archive, path),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlab_project_import.this", "path", path),
+					resource.TestCheckResourceAttr("gitlab_project_import.this", "import_status", "finished"),
+					resource.TestCheckResourceAttrSet("gitlab_project_import.this", "project_id"),
+					func(state *terraform.State) error {
+						projectID := state.RootModule().Resources["gitlab_project_import.this"].Primary.ID
+
+						imported, _, err := client.Projects.GetProject(projectID, nil)
+						if err != nil {
+							return err
+						}
+						if imported.Description != "An imported project" {
+							return fmt.Errorf("imported project description is %q; want %q", imported.Description, "An imported project")
+						}
+
+						_, _, err = client.RepositoryFiles.GetFile(projectID, "README.md", &gitlab.GetFileOptions{Ref: gitlab.String(project.DefaultBranch)}, nil)
+						if err != nil {
+							return fmt.Errorf("failed to get file from imported project: %w", err)
+						}
+
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+// testAccExportProject exports the given project and downloads the export archive into a temporary directory.
+func testAccExportProject(t *testing.T, client *gitlab.Client, project *gitlab.Project) string {
+	t.Helper()
+
+	if _, err := client.ProjectImportExport.ScheduleExport(project.ID, nil); err != nil {
+		t.Fatalf("could not schedule export of test project: %v", err)
+	}
+
+	for i := 0; ; i++ {
+		status, _, err := client.ProjectImportExport.ExportStatus(project.ID)
+		if err != nil {
+			t.Fatalf("could not get export status of test project: %v", err)
+		}
+		if status.ExportStatus == "finished" {
+			break
+		}
+		if i == 60 {
+			t.Fatalf("timed out waiting for export of test project, last status: %s", status.ExportStatus)
+		}
+		time.Sleep(5 * time.Second)
+	}
+
+	content, _, err := client.ProjectImportExport.ExportDownload(project.ID)
+	if err != nil {
+		t.Fatalf("could not download export of test project: %v", err)
+	}
+
+	archive := filepath.Join(t.TempDir(), "export.tar.gz")
+	if err := ioutil.WriteFile(archive, content, 0o600); err != nil {
+		t.Fatalf("could not write export archive of test project: %v", err)
+	}
+
+	return archive
+}
@@ -0,0 +1,565 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+// gitlabEnvironmentAccessDescription mirrors the deploy access levels and approval rules of a protected environment.
+type gitlabEnvironmentAccessDescription struct {
+	ID                     int                     `json:"id"`
+	AccessLevel            gitlab.AccessLevelValue `json:"access_level"`
+	AccessLevelDescription string                  `json:"access_level_description"`
+	UserID                 int                     `json:"user_id"`
+	GroupID                int                     `json:"group_id"`
+	RequiredApprovals      int                     `json:"required_approvals"`
+}
+
+// gitlabProtectedEnvironment extends the protected environment of go-gitlab with the approval settings.
+type gitlabProtectedEnvironment struct {
+	Name                  string                                `json:"name"`
+	DeployAccessLevels    []*gitlabEnvironmentAccessDescription `json:"deploy_access_levels"`
+	RequiredApprovalCount int                                   `json:"required_approval_count"`
+	ApprovalRules         []*gitlabEnvironmentAccessDescription `json:"approval_rules"`
+}
+
+// gitlabEnvironmentAccessOptions is a deploy access level or approval rule to create, change or destroy.
+type gitlabEnvironmentAccessOptions struct {
+	ID                *int                     `json:"id,omitempty"`
+	AccessLevel       *gitlab.AccessLevelValue `json:"access_level,omitempty"`
+	UserID            *int                     `json:"user_id,omitempty"`
+	GroupID           *int                     `json:"group_id,omitempty"`
+	RequiredApprovals *int                     `json:"required_approvals,omitempty"`
+	Destroy           *bool                    `json:"_destroy,omitempty"`
+}
+
+// gitlabProtectEnvironmentOptions are the options to protect or update a protected environment.
+type gitlabProtectEnvironmentOptions struct {
+	Name                  *string                           `json:"name,omitempty"`
+	DeployAccessLevels    []*gitlabEnvironmentAccessOptions `json:"deploy_access_levels,omitempty"`
+	RequiredApprovalCount *int                              `json:"required_approval_count,omitempty"`
+	ApprovalRules         []*gitlabEnvironmentAccessOptions `json:"approval_rules,omitempty"`
+}
+
+func schemaEnvironmentAccessElem(subject string, withApprovals bool) *schema.Resource {
+	// Only developers and maintainers can deploy to protected environments.
+	acceptedAccessLevels := make([]string, 0, 2)
+	for k, v := range accessLevelID {
+		if k != "master" && (v == gitlab.DeveloperPermissions || v == gitlab.MaintainerPermissions) {
+			acceptedAccessLevels = append(acceptedAccessLevels, k)
+		}
+	}
+
+	elem := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"access_level": {
+				Description:  fmt.Sprintf("Levels of access %s. Valid values are `developer` and `maintainer`. Mutually exclusive with `user_id` and `group_id`.", subject),
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(acceptedAccessLevels, false),
+			},
+			"access_level_description": {
+				Description: "Readable description of level of access.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"user_id": {
+				Description: fmt.Sprintf("The ID of a GitLab user %s. Mutually exclusive with `access_level` and `group_id`.", subject),
+				Type:        schema.TypeInt,
+				Optional:    true,
+			},
+			"group_id": {
+				Description: fmt.Sprintf("The ID of a GitLab group %s. Mutually exclusive with `access_level` and `user_id`.", subject),
+				Type:        schema.TypeInt,
+				Optional:    true,
+			},
+		},
+	}
+
+	if withApprovals {
+		elem.Schema["required_approvals"] = &schema.Schema{
+			Description:  "The number of approvals required from the users of this rule.",
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      1,
+			ValidateFunc: validation.IntAtLeast(1),
+		}
+	}
+
+	return elem
+}
+
+// schemaProtectedEnvironmentSettings returns the settings shared by project and group protected environments.
+func schemaProtectedEnvironmentSettings() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"deploy_access_levels": {
+			Description: "Array of access levels, users and groups allowed to deploy to the environment.",
+			Type:        schema.TypeSet,
+			Required:    true,
+			MinItems:    1,
+			Elem:        schemaEnvironmentAccessElem("allowed to deploy", false),
+		},
+		"required_approval_count": {
+			Description:  "The number of approvals required to deploy to the environment.",
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      0,
+			ValidateFunc: validation.IntAtLeast(0),
+		},
+		"approval_rules": {
+			Description: "Array of access levels, users and groups whose approvals are required to deploy to the environment.",
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Elem:        schemaEnvironmentAccessElem("required to approve deployments", true),
+		},
+	}
+}
+
+func resourceGitlabProjectProtectedEnvironment() *schema.Resource {
+	resourceSchema := schemaProtectedEnvironmentSettings()
+	resourceSchema["project"] = &schema.Schema{
+		Description:  "The ID or full path of the project.",
+		Type:         schema.TypeString,
+		Required:     true,
+		ForceNew:     true,
+		ValidateFunc: validation.StringIsNotEmpty,
+	}
+	resourceSchema["environment"] = &schema.Schema{
+		Description:  "The name of the environment to protect.",
+		Type:         schema.TypeString,
+		Required:     true,
+		ForceNew:     true,
+		ValidateFunc: validation.StringIsNotEmpty,
+	}
+
+	return &schema.Resource{
+		Description: "This resource allows you to protect an environment of a project, so that only the given users, groups and access levels can deploy to it.\n\n" +
+			"-> Protected environments require a GitLab Premium account or above. The `approval_rules` argument requires GitLab 14.10 or later. " +
+			"Please refer to [Gitlab API documentation](https://docs.gitlab.com/ee/api/protected_environments.html) for further information.",
+
+		CreateContext: resourceGitlabProjectProtectedEnvironmentCreate,
+		ReadContext:   resourceGitlabProjectProtectedEnvironmentRead,
+		UpdateContext: resourceGitlabProjectProtectedEnvironmentUpdate,
+		DeleteContext: resourceGitlabProjectProtectedEnvironmentDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: customizeDiffProtectedEnvironmentAccess,
+		Schema:        resourceSchema,
+	}
+}
+
+func resourceGitlabProjectProtectedEnvironmentCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	project := d.Get("project").(string)
+	environment := d.Get("environment").(string)
+
+	log.Printf("[DEBUG] create gitlab protected environment %q for project %s", environment, project)
+
+	pe, err := createGitlabProtectedEnvironment(ctx, client, fmt.Sprintf("projects/%s", gitlab.PathEscape(project)), d)
+	if err != nil {
+		return diag.Errorf("error protecting environment %q on project %q: %v", environment, project, err)
+	}
+
+	d.SetId(buildTwoPartID(&project, &pe.Name))
+
+	if err := checkProtectedEnvironmentFeatures(d, pe); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceGitlabProjectProtectedEnvironmentRead(ctx, d, meta)
+}
+
+func resourceGitlabProjectProtectedEnvironmentRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	project, environment, err := parseTwoPartID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] read gitlab protected environment %q for project %s", environment, project)
+
+	pe, err := getGitlabProtectedEnvironment(ctx, client, fmt.Sprintf("projects/%s", gitlab.PathEscape(project)), environment)
+	if err != nil {
+		if is404(err) {
+			log.Printf("[DEBUG] gitlab protected environment %q for project %s not found, removing from state", environment, project)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	d.Set("project", project)
+	d.Set("environment", pe.Name)
+
+	if err := setProtectedEnvironmentToState(d, pe); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceGitlabProjectProtectedEnvironmentUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	project := d.Get("project").(string)
+	environment := d.Get("environment").(string)
+
+	log.Printf("[DEBUG] update gitlab protected environment %q for project %s", environment, project)
+
+	if err := updateGitlabProtectedEnvironment(ctx, client, fmt.Sprintf("projects/%s", gitlab.PathEscape(project)), d); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceGitlabProjectProtectedEnvironmentRead(ctx, d, meta)
+}
+
+func resourceGitlabProjectProtectedEnvironmentDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	project := d.Get("project").(string)
+	environment := d.Get("environment").(string)
+
+	log.Printf("[DEBUG] delete gitlab protected environment %q for project %s", environment, project)
+
+	if err := deleteGitlabProtectedEnvironment(ctx, client, fmt.Sprintf("projects/%s", gitlab.PathEscape(project)), environment); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func setProtectedEnvironmentToState(d *schema.ResourceData, pe *gitlabProtectedEnvironment) error {
+	if err := d.Set("deploy_access_levels", flattenEnvironmentAccessDescriptions(pe.DeployAccessLevels, false)); err != nil {
+		return fmt.Errorf("error setting deploy_access_levels: %v", err)
+	}
+
+	d.Set("required_approval_count", pe.RequiredApprovalCount)
+
+	if err := d.Set("approval_rules", flattenEnvironmentAccessDescriptions(pe.ApprovalRules, true)); err != nil {
+		return fmt.Errorf("error setting approval_rules: %v", err)
+	}
+
+	return nil
+}
+
+func flattenEnvironmentAccessDescriptions(descriptions []*gitlabEnvironmentAccessDescription, withApprovals bool) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(descriptions))
+
+	for _, description := range descriptions {
+		values := map[string]interface{}{
+			"access_level_description": description.AccessLevelDescription,
+			"user_id":                  description.UserID,
+			"group_id":                 description.GroupID,
+		}
+		// The access level is only meaningful for role based entries.
+		if description.UserID == 0 && description.GroupID == 0 {
+			values["access_level"] = accessLevel[description.AccessLevel]
+		}
+		if withApprovals {
+			values["required_approvals"] = description.RequiredApprovals
+		}
+		result = append(result, values)
+	}
+
+	return result
+}
+
+func expandEnvironmentAccessOptions(values []interface{}, withApprovals bool) []*gitlabEnvironmentAccessOptions {
+	result := make([]*gitlabEnvironmentAccessOptions, 0, len(values))
+
+	for _, v := range values {
+		m := v.(map[string]interface{})
+		options := &gitlabEnvironmentAccessOptions{}
+
+		// customizeDiffProtectedEnvironmentAccess ensures that exactly one of them is set.
+		if level := m["access_level"].(string); level != "" {
+			options.AccessLevel = gitlab.AccessLevel(accessLevelID[level])
+		}
+		if userID := m["user_id"].(int); userID != 0 {
+			options.UserID = gitlab.Int(userID)
+		}
+		if groupID := m["group_id"].(int); groupID != 0 {
+			options.GroupID = gitlab.Int(groupID)
+		}
+
+		if withApprovals {
+			options.RequiredApprovals = gitlab.Int(m["required_approvals"].(int))
+		}
+
+		result = append(result, options)
+	}
+
+	return result
+}
+
+func expandProtectEnvironmentOptions(d *schema.ResourceData) *gitlabProtectEnvironmentOptions {
+	options := &gitlabProtectEnvironmentOptions{
+		Name:               gitlab.String(d.Get("environment").(string)),
+		DeployAccessLevels: expandEnvironmentAccessOptions(d.Get("deploy_access_levels").(*schema.Set).List(), false),
+		ApprovalRules:      expandEnvironmentAccessOptions(d.Get("approval_rules").(*schema.Set).List(), true),
+	}
+
+	if v := d.Get("required_approval_count").(int); v > 0 {
+		options.RequiredApprovalCount = gitlab.Int(v)
+	}
+
+	return options
+}
+
+// customizeDiffProtectedEnvironmentAccess checks that exactly one of access_level, user_id and group_id is set
+// in every deploy access level and approval rule, so that invalid settings fail the plan instead of the apply.
+func customizeDiffProtectedEnvironmentAccess(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() {
+		return nil
+	}
+
+	for _, key := range []string{"deploy_access_levels", "approval_rules"} {
+		values := config.GetAttr(key)
+		if values.IsNull() || !values.IsKnown() {
+			continue
+		}
+
+		for it := values.ElementIterator(); it.Next(); {
+			_, value := it.Element()
+			if err := validateEnvironmentAccessConfig(value); err != nil {
+				return fmt.Errorf("invalid %s: %v", key, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateEnvironmentAccessConfig checks the configuration of a deploy access level or approval rule.
+// Values which aren't known yet are checked during the apply.
+func validateEnvironmentAccessConfig(value cty.Value) error {
+	if value.IsNull() || !value.IsKnown() {
+		return nil
+	}
+
+	set := 0
+	for _, attr := range []string{"access_level", "user_id", "group_id"} {
+		v := value.GetAttr(attr)
+		if !v.IsKnown() {
+			return nil
+		}
+		if !v.IsNull() {
+			set++
+		}
+	}
+
+	if set != 1 {
+		return fmt.Errorf("exactly one of access_level, user_id and group_id must be set")
+	}
+
+	return nil
+}
+
+// expandProtectEnvironmentOptionsFrom builds the options to protect the environment again with the settings of the given protected environment.
+func expandProtectEnvironmentOptionsFrom(pe *gitlabProtectedEnvironment) *gitlabProtectEnvironmentOptions {
+	options := &gitlabProtectEnvironmentOptions{
+		Name:               gitlab.String(pe.Name),
+		DeployAccessLevels: expandEnvironmentAccessOptionsFrom(pe.DeployAccessLevels),
+		ApprovalRules:      expandEnvironmentAccessOptionsFrom(pe.ApprovalRules),
+	}
+
+	if pe.RequiredApprovalCount > 0 {
+		options.RequiredApprovalCount = gitlab.Int(pe.RequiredApprovalCount)
+	}
+
+	return options
+}
+
+func expandEnvironmentAccessOptionsFrom(descriptions []*gitlabEnvironmentAccessDescription) []*gitlabEnvironmentAccessOptions {
+	options := make([]*gitlabEnvironmentAccessOptions, 0, len(descriptions))
+
+	for _, description := range descriptions {
+		option := &gitlabEnvironmentAccessOptions{}
+		switch {
+		case description.UserID != 0:
+			option.UserID = gitlab.Int(description.UserID)
+		case description.GroupID != 0:
+			option.GroupID = gitlab.Int(description.GroupID)
+		default:
+			option.AccessLevel = gitlab.AccessLevel(description.AccessLevel)
+		}
+		if description.RequiredApprovals > 0 {
+			option.RequiredApprovals = gitlab.Int(description.RequiredApprovals)
+		}
+		options = append(options, option)
+	}
+
+	return options
+}
+
+// expandEnvironmentAccessChanges computes the changes to turn the existing deploy access levels or approval rules
+// of a protected environment into the wanted ones.
+func expandEnvironmentAccessChanges(existing []*gitlabEnvironmentAccessDescription, wanted []*gitlabEnvironmentAccessOptions) []*gitlabEnvironmentAccessOptions {
+	wantedByKey := make(map[string]*gitlabEnvironmentAccessOptions)
+	for _, options := range wanted {
+		wantedByKey[environmentAccessOptionsKey(options)] = options
+	}
+
+	changes := make([]*gitlabEnvironmentAccessOptions, 0)
+	for _, description := range existing {
+		key := environmentAccessDescriptionKey(description)
+		options, ok := wantedByKey[key]
+		if !ok {
+			changes = append(changes, &gitlabEnvironmentAccessOptions{
+				ID:      gitlab.Int(description.ID),
+				Destroy: gitlab.Bool(true),
+			})
+			continue
+		}
+
+		delete(wantedByKey, key)
+		if options.RequiredApprovals != nil && *options.RequiredApprovals != description.RequiredApprovals {
+			changes = append(changes, &gitlabEnvironmentAccessOptions{
+				ID:                gitlab.Int(description.ID),
+				RequiredApprovals: options.RequiredApprovals,
+			})
+		}
+	}
+
+	keys := make([]string, 0, len(wantedByKey))
+	for key := range wantedByKey {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		changes = append(changes, wantedByKey[key])
+	}
+
+	return changes
+}
+
+func environmentAccessDescriptionKey(description *gitlabEnvironmentAccessDescription) string {
+	switch {
+	case description.UserID != 0:
+		return fmt.Sprintf("user:%d", description.UserID)
+	case description.GroupID != 0:
+		return fmt.Sprintf("group:%d", description.GroupID)
+	default:
+		return fmt.Sprintf("access_level:%d", description.AccessLevel)
+	}
+}
+
+func environmentAccessOptionsKey(options *gitlabEnvironmentAccessOptions) string {
+	switch {
+	case options.UserID != nil:
+		return fmt.Sprintf("user:%d", *options.UserID)
+	case options.GroupID != nil:
+		return fmt.Sprintf("group:%d", *options.GroupID)
+	default:
+		return fmt.Sprintf("access_level:%d", *options.AccessLevel)
+	}
+}
+
+// createGitlabProtectedEnvironment protects the environment of the resource for the project or group at base.
+func createGitlabProtectedEnvironment(ctx context.Context, client *gitlab.Client, base string, d *schema.ResourceData) (*gitlabProtectedEnvironment, error) {
+	return doGitlabProtectedEnvironmentRequest(ctx, client, http.MethodPost, fmt.Sprintf("%s/protected_environments", base), expandProtectEnvironmentOptions(d))
+}
+
+// updateGitlabProtectedEnvironment updates the protected environment of the resource for the project or group at base.
+// GitLab versions before 15.4 can't update protected environments, so the environment is protected again instead.
+func updateGitlabProtectedEnvironment(ctx context.Context, client *gitlab.Client, base string, d *schema.ResourceData) error {
+	environment := d.Get("environment").(string)
+	u := fmt.Sprintf("%s/protected_environments/%s", base, gitlab.PathEscape(environment))
+
+	options := expandProtectEnvironmentOptions(d)
+
+	isUpdateSupported, err := isGitLabVersionAtLeast(client, "15.4")()
+	if err != nil {
+		return err
+	}
+
+	existing, err := doGitlabProtectedEnvironmentRequest(ctx, client, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+
+	if !isUpdateSupported {
+		log.Printf("[DEBUG] replace gitlab protected environment %s", u)
+		if err := deleteGitlabProtectedEnvironment(ctx, client, base, environment); err != nil {
+			return err
+		}
+
+		pe, err := doGitlabProtectedEnvironmentRequest(ctx, client, http.MethodPost, fmt.Sprintf("%s/protected_environments", base), options)
+		if err != nil {
+			// Don't leave the environment unprotected, but restore the previous settings.
+			log.Printf("[DEBUG] restore previous gitlab protected environment %s", u)
+			if _, restoreErr := doGitlabProtectedEnvironmentRequest(ctx, client, http.MethodPost, fmt.Sprintf("%s/protected_environments", base), expandProtectEnvironmentOptionsFrom(existing)); restoreErr != nil {
+				return fmt.Errorf("failed to protect environment %q: %v; the environment is now UNPROTECTED, restoring the previous protection failed: %v", environment, err, restoreErr)
+			}
+			return fmt.Errorf("failed to protect environment %q, the previous protection has been restored: %v", environment, err)
+		}
+
+		return checkProtectedEnvironmentFeatures(d, pe)
+	}
+
+	changes := &gitlabProtectEnvironmentOptions{
+		DeployAccessLevels:    expandEnvironmentAccessChanges(existing.DeployAccessLevels, options.DeployAccessLevels),
+		RequiredApprovalCount: gitlab.Int(d.Get("required_approval_count").(int)),
+		ApprovalRules:         expandEnvironmentAccessChanges(existing.ApprovalRules, options.ApprovalRules),
+	}
+
+	pe, err := doGitlabProtectedEnvironmentRequest(ctx, client, http.MethodPut, u, changes)
+	if err != nil {
+		return err
+	}
+
+	return checkProtectedEnvironmentFeatures(d, pe)
+}
+
+// checkProtectedEnvironmentFeatures returns an error if GitLab silently ignored some of the requested settings.
+func checkProtectedEnvironmentFeatures(d *schema.ResourceData, pe *gitlabProtectedEnvironment) error {
+	if d.Get("approval_rules").(*schema.Set).Len() > 0 && len(pe.ApprovalRules) == 0 {
+		return fmt.Errorf("feature unavailable: approval_rules")
+	}
+	if d.Get("required_approval_count").(int) > 0 && pe.RequiredApprovalCount == 0 {
+		return fmt.Errorf("feature unavailable: required_approval_count")
+	}
+	return nil
+}
+
+func getGitlabProtectedEnvironment(ctx context.Context, client *gitlab.Client, base string, environment string) (*gitlabProtectedEnvironment, error) {
+	// go-gitlab doesn't expose the approval settings of protected environments
+	u := fmt.Sprintf("%s/protected_environments/%s", base, gitlab.PathEscape(environment))
+	return doGitlabProtectedEnvironmentRequest(ctx, client, http.MethodGet, u, nil)
+}
+
+func deleteGitlabProtectedEnvironment(ctx context.Context, client *gitlab.Client, base string, environment string) error {
+	u := fmt.Sprintf("%s/protected_environments/%s", base, gitlab.PathEscape(environment))
+
+	req, err := client.NewRequest(http.MethodDelete, u, nil, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Do(req, nil)
+	return err
+}
+
+func doGitlabProtectedEnvironmentRequest(ctx context.Context, client *gitlab.Client, method string, u string, options interface{}) (*gitlabProtectedEnvironment, error) {
+	req, err := client.NewRequest(method, u, options, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+
+	pe := new(gitlabProtectedEnvironment)
+	if _, err := client.Do(req, pe); err != nil {
+		return nil, err
+	}
+
+	return pe, nil
+}
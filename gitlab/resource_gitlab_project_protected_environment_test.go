@@ -0,0 +1,185 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/xanzy/go-gitlab"
+)
+
+func TestGitlabProtectedEnvironment_expandEnvironmentAccessChanges(t *testing.T) {
+	existing := []*gitlabEnvironmentAccessDescription{
+		{ID: 1, AccessLevel: gitlab.MaintainerPermissions},
+		{ID: 2, UserID: 42, RequiredApprovals: 1},
+		{ID: 3, GroupID: 7, RequiredApprovals: 2},
+	}
+	wanted := []*gitlabEnvironmentAccessOptions{
+		{AccessLevel: gitlab.AccessLevel(gitlab.DeveloperPermissions)},
+		{UserID: gitlab.Int(42), RequiredApprovals: gitlab.Int(3)},
+		{GroupID: gitlab.Int(7), RequiredApprovals: gitlab.Int(2)},
+	}
+
+	expected := []*gitlabEnvironmentAccessOptions{
+		{ID: gitlab.Int(1), Destroy: gitlab.Bool(true)},
+		{ID: gitlab.Int(2), RequiredApprovals: gitlab.Int(3)},
+		{AccessLevel: gitlab.AccessLevel(gitlab.DeveloperPermissions)},
+	}
+
+	got := expandEnvironmentAccessChanges(existing, wanted)
+	if !reflect.DeepEqual(got, expected) {
+		gotJSON, _ := json.Marshal(got)
+		wantJSON, _ := json.Marshal(expected)
+		t.Errorf("got %s; want %s", gotJSON, wantJSON)
+	}
+}
+
+func TestGitlabProtectedEnvironment_validateEnvironmentAccessConfig(t *testing.T) {
+	access := func(accessLevel, userID, groupID cty.Value) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{
+			"access_level": accessLevel,
+			"user_id":      userID,
+			"group_id":     groupID,
+		})
+	}
+
+	cases := []struct {
+		Value    cty.Value
+		ExpectOK bool
+	}{
+		{Value: access(cty.StringVal("developer"), cty.NullVal(cty.Number), cty.NullVal(cty.Number)), ExpectOK: true},
+		{Value: access(cty.NullVal(cty.String), cty.NumberIntVal(42), cty.NullVal(cty.Number)), ExpectOK: true},
+		{Value: access(cty.NullVal(cty.String), cty.NullVal(cty.Number), cty.NumberIntVal(7)), ExpectOK: true},
+		// The user ID is only known during the apply.
+		{Value: access(cty.NullVal(cty.String), cty.UnknownVal(cty.Number), cty.NullVal(cty.Number)), ExpectOK: true},
+		{Value: access(cty.NullVal(cty.String), cty.NullVal(cty.Number), cty.NullVal(cty.Number)), ExpectOK: false},
+		{Value: access(cty.StringVal("developer"), cty.NumberIntVal(42), cty.NullVal(cty.Number)), ExpectOK: false},
+		{Value: access(cty.NullVal(cty.String), cty.NumberIntVal(42), cty.NumberIntVal(7)), ExpectOK: false},
+	}
+
+	for _, tc := range cases {
+		err := validateEnvironmentAccessConfig(tc.Value)
+		if tc.ExpectOK && err != nil {
+			t.Errorf("unexpected error for %#v: %v", tc.Value, err)
+		}
+		if !tc.ExpectOK && err == nil {
+			t.Errorf("expected an error for %#v", tc.Value)
+		}
+	}
+}
+
+func TestAccGitlabProjectProtectedEnvironment_basic(t *testing.T) {
+	testAccCheck(t)
+
+	client := testAccNewClient(t)
+	testAccCheckEE(t, client)
+
+	project := testAccCreateProject(t, client)
+	users := testAccCreateUsers(t, client, 1)
+	if _, _, err := client.ProjectMembers.AddProjectMember(project.ID, &gitlab.AddProjectMemberOptions{
+		UserID:      gitlab.Int(users[0].ID),
+		AccessLevel: gitlab.AccessLevel(gitlab.DeveloperPermissions),
+	}); err != nil {
+		t.Fatalf("could not add user to project: %v", err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabProtectedEnvironmentDestroy(client, "gitlab_project_protected_environment", "projects"),
+		Steps: []resource.TestStep{
+			// Protect an environment for maintainers
+			{
+				Config: fmt.Sprintf(`
+resource "gitlab_project_protected_environment" "this" {
+  project     = %d
+  environment = "production"
+
+  deploy_access_levels {
+    access_level = "maintainer"
+  }
+}
+`, project.ID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlab_project_protected_environment.this", "deploy_access_levels.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs("gitlab_project_protected_environment.this", "deploy_access_levels.*", map[string]string{
+						"access_level": "maintainer",
+					}),
+					resource.TestCheckResourceAttr("gitlab_project_protected_environment.this", "required_approval_count", "0"),
+				),
+			},
+			// Verify import
+			{
+				ResourceName:      "gitlab_project_protected_environment.this",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Allow a user to deploy and require approvals
+			{
+				Config: fmt.Sprintf(`
+resource "gitlab_project_protected_environment" "this" {
+  project     = %d
+  environment = "production"
+
+  deploy_access_levels {
+    access_level = "maintainer"
+  }
+
+  deploy_access_levels {
+    user_id = %d
+  }
+
+  approval_rules {
+    access_level       = "maintainer"
+    required_approvals = 2
+  }
+}
+`, project.ID, users[0].ID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlab_project_protected_environment.this", "deploy_access_levels.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("gitlab_project_protected_environment.this", "deploy_access_levels.*", map[string]string{
+						"user_id": fmt.Sprintf("%d", users[0].ID),
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("gitlab_project_protected_environment.this", "approval_rules.*", map[string]string{
+						"access_level":       "maintainer",
+						"required_approvals": "2",
+					}),
+				),
+			},
+			// Verify import
+			{
+				ResourceName:      "gitlab_project_protected_environment.this",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckGitlabProtectedEnvironmentDestroy(client *gitlab.Client, resourceType string, kind string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != resourceType {
+				continue
+			}
+
+			id, environment, err := parseTwoPartID(rs.Primary.ID)
+			if err != nil {
+				return err
+			}
+
+			_, err = getGitlabProtectedEnvironment(context.Background(), client, fmt.Sprintf("%s/%s", kind, gitlab.PathEscape(id)), environment)
+			if err == nil {
+				return fmt.Errorf("protected environment %s still exists", rs.Primary.ID)
+			}
+			if !is404(err) {
+				return err
+			}
+		}
+		return nil
+	}
+}
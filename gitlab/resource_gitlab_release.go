@@ -0,0 +1,484 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+var releaseLinkTypes = []string{
+	string(gitlab.OtherLinkType),
+	string(gitlab.RunbookLinkType),
+	string(gitlab.ImageLinkType),
+	string(gitlab.PackageLinkType),
+}
+
+func resourceGitlabRelease() *schema.Resource {
+	return &schema.Resource{
+		Description: "This resource allows you to create and manage releases of a project. " +
+			"Deleting the release does not delete the tag it belongs to.",
+
+		CreateContext: resourceGitlabReleaseCreate,
+		ReadContext:   resourceGitlabReleaseRead,
+		UpdateContext: resourceGitlabReleaseUpdate,
+		DeleteContext: resourceGitlabReleaseDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Description: "The ID or full path of the project.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"tag_name": {
+				Description: "The tag of the release.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"ref": {
+				Description: "The branch name or commit SHA to create the tag from, if `tag_name` doesn't exist yet. It is only used during creation and is not read back.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					// The ref is unknown for imported releases
+					return old == "" && d.Id() != ""
+				},
+			},
+			"name": {
+				Description: "The name of the release. Defaults to the `tag_name`.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"description": {
+				Description: "The description of the release. You can use Markdown.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"milestones": {
+				Description: "The titles of the milestones the release is associated with.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+			},
+			"released_at": {
+				Description:      "The date when the release is or will be ready, in ISO 8601 format (e.g. `2021-03-15T08:00:00Z`). Defaults to the current time.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateFunc:     validation.IsRFC3339Time,
+				DiffSuppressFunc: suppressEquivalentRFC3339Time,
+			},
+			"assets": {
+				Description: "The assets of the release.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"links": {
+							Description: "The asset links of the release.",
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"id": {
+										Description: "The ID of the link.",
+										Type:        schema.TypeInt,
+										Computed:    true,
+									},
+									"name": {
+										Description: "The name of the link. Link names must be unique within the release.",
+										Type:        schema.TypeString,
+										Required:    true,
+									},
+									"url": {
+										Description:  "The URL of the link.",
+										Type:         schema.TypeString,
+										Required:     true,
+										ValidateFunc: validateURLFunc,
+									},
+									"filepath": {
+										Description: "The optional path for a direct asset link, e.g. `/binaries/linux-amd64`.",
+										Type:        schema.TypeString,
+										Optional:    true,
+									},
+									"link_type": {
+										Description:  "The type of the link. Valid values are `other`, `runbook`, `image` and `package`.",
+										Type:         schema.TypeString,
+										Optional:     true,
+										Default:      string(gitlab.OtherLinkType),
+										ValidateFunc: validation.StringInSlice(releaseLinkTypes, false),
+									},
+									"direct_asset_url": {
+										Description: "The full URL of the direct asset link.",
+										Type:        schema.TypeString,
+										Computed:    true,
+									},
+									"external": {
+										Description: "Whether the link points to an external resource.",
+										Type:        schema.TypeBool,
+										Computed:    true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"created_at": {
+				Description: "The date when the release was created.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"commit_sha": {
+				Description: "The SHA of the commit the release tag points to.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// gitlabRelease extends gitlab.Release with the milestones of the release, which aren't decoded by go-gitlab.
+type gitlabRelease struct {
+	gitlab.Release
+	Milestones []*gitlab.Milestone `json:"milestones"`
+}
+
+func resourceGitlabReleaseCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	project := d.Get("project").(string)
+	tagName := d.Get("tag_name").(string)
+
+	name := tagName
+	if v, ok := d.GetOk("name"); ok {
+		name = v.(string)
+	}
+
+	options := &gitlab.CreateReleaseOptions{
+		Name:        gitlab.String(name),
+		TagName:     gitlab.String(tagName),
+		Description: gitlab.String(d.Get("description").(string)),
+		Milestones:  stringSetToStringSlice(d.Get("milestones").(*schema.Set)),
+	}
+	if v, ok := d.GetOk("ref"); ok {
+		options.Ref = gitlab.String(v.(string))
+	}
+	if v, ok := d.GetOk("released_at"); ok {
+		releasedAt, err := time.Parse(time.RFC3339, v.(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		options.ReleasedAt = &releasedAt
+	}
+	if links := expandReleaseAssetLinkOptions(d.Get("assets.0.links").([]interface{})); len(links) > 0 {
+		options.Assets = &gitlab.ReleaseAssetsOptions{Links: links}
+	}
+
+	log.Printf("[DEBUG] create gitlab release %q for project %s", tagName, project)
+
+	release, _, err := client.Releases.CreateRelease(project, options, gitlab.WithContext(ctx))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(buildTwoPartID(&project, &release.TagName))
+
+	return resourceGitlabReleaseRead(ctx, d, meta)
+}
+
+func resourceGitlabReleaseRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	project, tagName, err := parseTwoPartID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] read gitlab release %q for project %s", tagName, project)
+
+	release, err := getGitlabRelease(ctx, client, project, tagName)
+	if err != nil {
+		if is404(err) {
+			log.Printf("[DEBUG] gitlab release %q for project %s not found, removing from state", tagName, project)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	d.Set("project", project)
+	d.Set("tag_name", release.TagName)
+	d.Set("name", release.Name)
+	d.Set("description", release.Description)
+	d.Set("commit_sha", release.Commit.ID)
+	if release.ReleasedAt != nil {
+		d.Set("released_at", release.ReleasedAt.Format(time.RFC3339))
+	}
+	if release.CreatedAt != nil {
+		d.Set("created_at", release.CreatedAt.Format(time.RFC3339))
+	}
+	if err := d.Set("milestones", flattenReleaseMilestones(release.Milestones)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("assets", flattenReleaseAssets(release, d.Get("assets.0.links").([]interface{}))); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceGitlabReleaseUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	project, tagName, err := parseTwoPartID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChanges("name", "description", "milestones", "released_at") {
+		options := &gitlab.UpdateReleaseOptions{
+			Name:        gitlab.String(d.Get("name").(string)),
+			Description: gitlab.String(d.Get("description").(string)),
+			Milestones:  stringSetToStringSlice(d.Get("milestones").(*schema.Set)),
+		}
+		if v, ok := d.GetOk("released_at"); ok && d.HasChange("released_at") {
+			releasedAt, err := time.Parse(time.RFC3339, v.(string))
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			options.ReleasedAt = &releasedAt
+		}
+
+		log.Printf("[DEBUG] update gitlab release %q for project %s", tagName, project)
+
+		if _, _, err := client.Releases.UpdateRelease(project, tagName, options, gitlab.WithContext(ctx)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("assets") {
+		if err := updateReleaseLinks(ctx, client, project, tagName, d); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceGitlabReleaseRead(ctx, d, meta)
+}
+
+func resourceGitlabReleaseDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	project, tagName, err := parseTwoPartID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] delete gitlab release %q for project %s", tagName, project)
+
+	if _, _, err := client.Releases.DeleteRelease(project, tagName, gitlab.WithContext(ctx)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// updateReleaseLinks updates the asset links of a release in place.
+// Links are matched by their name, which is unique within a release.
+func updateReleaseLinks(ctx context.Context, client *gitlab.Client, project, tagName string, d *schema.ResourceData) error {
+	oldLinks := make(map[string]map[string]interface{})
+	o, _ := d.GetChange("assets.0.links")
+	for _, v := range o.([]interface{}) {
+		link := v.(map[string]interface{})
+		oldLinks[link["name"].(string)] = link
+	}
+
+	newLinks := make(map[string]map[string]interface{})
+	for _, v := range d.Get("assets.0.links").([]interface{}) {
+		link := v.(map[string]interface{})
+		newLinks[link["name"].(string)] = link
+	}
+
+	// The URLs and file paths of the links must be unique within the release,
+	// so removed links are deleted first, to free them for renamed links.
+	for name, oldLink := range oldLinks {
+		if _, ok := newLinks[name]; ok {
+			continue
+		}
+
+		log.Printf("[DEBUG] delete gitlab release %q link %q for project %s", tagName, name, project)
+		if _, _, err := client.ReleaseLinks.DeleteReleaseLink(project, tagName, oldLink["id"].(int), gitlab.WithContext(ctx)); err != nil {
+			return err
+		}
+	}
+
+	for _, v := range d.Get("assets.0.links").([]interface{}) {
+		link := v.(map[string]interface{})
+		name := link["name"].(string)
+
+		oldLink, ok := oldLinks[name]
+		if !ok || oldLink["url"] == link["url"] && oldLink["filepath"] == link["filepath"] && oldLink["link_type"] == link["link_type"] {
+			continue
+		}
+
+		log.Printf("[DEBUG] update gitlab release %q link %q for project %s", tagName, name, project)
+		_, _, err := client.ReleaseLinks.UpdateReleaseLink(project, tagName, oldLink["id"].(int), &gitlab.UpdateReleaseLinkOptions{
+			URL:      gitlab.String(link["url"].(string)),
+			FilePath: releaseLinkFilePath(link),
+			LinkType: gitlab.LinkType(gitlab.LinkTypeValue(link["link_type"].(string))),
+		}, gitlab.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, v := range d.Get("assets.0.links").([]interface{}) {
+		link := v.(map[string]interface{})
+		name := link["name"].(string)
+
+		if _, ok := oldLinks[name]; ok {
+			continue
+		}
+
+		log.Printf("[DEBUG] create gitlab release %q link %q for project %s", tagName, name, project)
+		_, _, err := client.ReleaseLinks.CreateReleaseLink(project, tagName, &gitlab.CreateReleaseLinkOptions{
+			Name:     gitlab.String(name),
+			URL:      gitlab.String(link["url"].(string)),
+			FilePath: releaseLinkFilePath(link),
+			LinkType: gitlab.LinkType(gitlab.LinkTypeValue(link["link_type"].(string))),
+		}, gitlab.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func getGitlabRelease(ctx context.Context, client *gitlab.Client, project, tagName string) (*gitlabRelease, error) {
+	u := fmt.Sprintf("projects/%s/releases/%s", gitlab.PathEscape(project), gitlab.PathEscape(tagName))
+
+	req, err := client.NewRequest(http.MethodGet, u, nil, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+
+	release := new(gitlabRelease)
+	if _, err := client.Do(req, release); err != nil {
+		return nil, err
+	}
+
+	return release, nil
+}
+
+func expandReleaseAssetLinkOptions(links []interface{}) []*gitlab.ReleaseAssetLinkOptions {
+	options := make([]*gitlab.ReleaseAssetLinkOptions, 0, len(links))
+	for _, v := range links {
+		link := v.(map[string]interface{})
+		options = append(options, &gitlab.ReleaseAssetLinkOptions{
+			Name:     gitlab.String(link["name"].(string)),
+			URL:      gitlab.String(link["url"].(string)),
+			FilePath: releaseLinkFilePath(link),
+			LinkType: gitlab.LinkType(gitlab.LinkTypeValue(link["link_type"].(string))),
+		})
+	}
+	return options
+}
+
+func releaseLinkFilePath(link map[string]interface{}) *string {
+	if v, ok := link["filepath"].(string); ok && v != "" {
+		return gitlab.String(v)
+	}
+	return nil
+}
+
+func flattenReleaseMilestones(milestones []*gitlab.Milestone) []string {
+	titles := make([]string, 0, len(milestones))
+	for _, milestone := range milestones {
+		titles = append(titles, milestone.Title)
+	}
+	return titles
+}
+
+// flattenReleaseAssets flattens the asset links of the release. The links are kept in the order of
+// the currentLinks, links which are not part of them are ordered by their creation.
+func flattenReleaseAssets(release *gitlabRelease, currentLinks []interface{}) []interface{} {
+	if len(release.Assets.Links) == 0 {
+		return nil
+	}
+
+	position := make(map[string]int, len(currentLinks))
+	for i, v := range currentLinks {
+		if link, ok := v.(map[string]interface{}); ok {
+			position[link["name"].(string)] = i
+		}
+	}
+
+	releaseLinks := make([]*gitlab.ReleaseLink, len(release.Assets.Links))
+	copy(releaseLinks, release.Assets.Links)
+	sort.SliceStable(releaseLinks, func(i, j int) bool {
+		pi, iok := position[releaseLinks[i].Name]
+		pj, jok := position[releaseLinks[j].Name]
+		switch {
+		case iok && jok:
+			return pi < pj
+		case iok != jok:
+			return iok
+		}
+		return releaseLinks[i].ID < releaseLinks[j].ID
+	})
+
+	links := make([]interface{}, 0, len(releaseLinks))
+	for _, link := range releaseLinks {
+		links = append(links, map[string]interface{}{
+			"id":               link.ID,
+			"name":             link.Name,
+			"url":              link.URL,
+			"filepath":         releaseLinkFilePathFromDirectAssetURL(link, release.TagName),
+			"link_type":        string(link.LinkType),
+			"direct_asset_url": link.DirectAssetURL,
+			"external":         link.External,
+		})
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"links": links,
+		},
+	}
+}
+
+// releaseLinkFilePathFromDirectAssetURL extracts the filepath of a direct asset link,
+// which isn't returned by the API, from its direct asset URL.
+func releaseLinkFilePathFromDirectAssetURL(link *gitlab.ReleaseLink, tagName string) string {
+	marker := fmt.Sprintf("/-/releases/%s/downloads", tagName)
+	if i := strings.Index(link.DirectAssetURL, marker); i != -1 {
+		return link.DirectAssetURL[i+len(marker):]
+	}
+	return ""
+}
+
+func suppressEquivalentRFC3339Time(k, old, new string, d *schema.ResourceData) bool {
+	oldTime, err := time.Parse(time.RFC3339, old)
+	if err != nil {
+		return false
+	}
+	newTime, err := time.Parse(time.RFC3339, new)
+	if err != nil {
+		return false
+	}
+	return oldTime.Equal(newTime)
+}
@@ -0,0 +1,150 @@
+package gitlab
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func TestGitlabRelease_linkFilePathFromDirectAssetURL(t *testing.T) {
+	cases := []struct {
+		url      string
+		expected string
+	}{
+		{url: "https://gitlab.com/foo/bar/-/releases/v1.0.0/downloads/bin/meow", expected: "/bin/meow"},
+		{url: "https://gitlab.com/foo/bar/-/releases/v2.0.0/downloads/bin/meow", expected: ""},
+		{url: "https://example.com/meow", expected: ""},
+	}
+
+	for _, c := range cases {
+		link := &gitlab.ReleaseLink{DirectAssetURL: c.url}
+		if actual := releaseLinkFilePathFromDirectAssetURL(link, "v1.0.0"); actual != c.expected {
+			t.Errorf("direct asset url %q: got %q; want %q", c.url, actual, c.expected)
+		}
+	}
+}
+
+func TestAccGitlabRelease_basic(t *testing.T) {
+	testAccCheck(t)
+
+	client := testAccNewClient(t)
+	project := testAccCreateProject(t, client)
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabReleaseDestroy(client, project),
+		Steps: []resource.TestStep{
+			// Create a release together with its tag
+			{
+				Config: fmt.Sprintf(`
+resource "gitlab_release" "this" {
+  project     = %d
+  tag_name    = "v1.0.0"
+  ref         = %q
+  name        = "Meow v1.0.0"
+  description = "The first release"
+  released_at = "2021-12-01T10:00:00Z"
+
+  assets {
+    links {
+      name      = "binary"
+      url       = "https://example.com/meow"
+      filepath  = "/bin/meow"
+      link_type = "package"
+    }
+    links {
+      name = "docs"
+      url  = "https://example.com/docs"
+    }
+  }
+}
+				`, project.ID, project.DefaultBranch),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlab_release.this", "name", "Meow v1.0.0"),
+					resource.TestCheckResourceAttrSet("gitlab_release.this", "commit_sha"),
+					resource.TestCheckResourceAttr("gitlab_release.this", "assets.0.links.#", "2"),
+					resource.TestCheckResourceAttr("gitlab_release.this", "assets.0.links.0.filepath", "/bin/meow"),
+					resource.TestCheckResourceAttr("gitlab_release.this", "assets.0.links.0.link_type", "package"),
+					resource.TestCheckResourceAttr("gitlab_release.this", "assets.0.links.1.link_type", "other"),
+				),
+			},
+			// Update the release and its links in place
+			{
+				Config: fmt.Sprintf(`
+resource "gitlab_release" "this" {
+  project     = %d
+  tag_name    = "v1.0.0"
+  ref         = %q
+  name        = "Meow v1.0.0"
+  description = "The first release, now with updated assets"
+  released_at = "2021-12-01T10:00:00Z"
+
+  assets {
+    links {
+      name      = "binary"
+      url       = "https://example.com/meow-v2"
+      filepath  = "/bin/meow"
+      link_type = "package"
+    }
+  }
+}
+				`, project.ID, project.DefaultBranch),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlab_release.this", "description", "The first release, now with updated assets"),
+					resource.TestCheckResourceAttr("gitlab_release.this", "assets.0.links.#", "1"),
+					resource.TestCheckResourceAttr("gitlab_release.this", "assets.0.links.0.url", "https://example.com/meow-v2"),
+				),
+			},
+			// Rename a link, keeping its URL and file path
+			{
+				Config: fmt.Sprintf(`
+resource "gitlab_release" "this" {
+  project     = %d
+  tag_name    = "v1.0.0"
+  ref         = %q
+  name        = "Meow v1.0.0"
+  description = "The first release, now with updated assets"
+  released_at = "2021-12-01T10:00:00Z"
+
+  assets {
+    links {
+      name      = "meow binary"
+      url       = "https://example.com/meow-v2"
+      filepath  = "/bin/meow"
+      link_type = "package"
+    }
+  }
+}
+				`, project.ID, project.DefaultBranch),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlab_release.this", "assets.0.links.#", "1"),
+					resource.TestCheckResourceAttr("gitlab_release.this", "assets.0.links.0.name", "meow binary"),
+					resource.TestCheckResourceAttr("gitlab_release.this", "assets.0.links.0.url", "https://example.com/meow-v2"),
+					resource.TestCheckResourceAttr("gitlab_release.this", "assets.0.links.0.filepath", "/bin/meow"),
+				),
+			},
+			{
+				ResourceName:            "gitlab_release.this",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"ref"},
+			},
+		},
+	})
+}
+
+func testAccCheckGitlabReleaseDestroy(client *gitlab.Client, project *gitlab.Project) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		_, _, err := client.Releases.GetRelease(project.ID, "v1.0.0")
+		if err == nil {
+			return fmt.Errorf("release %q still exists", "v1.0.0")
+		}
+		if !is404(err) {
+			return err
+		}
+		return nil
+	}
+}
@@ -0,0 +1,556 @@
+package gitlab
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"log"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func resourceGitlabRepositoryDirectory() *schema.Resource {
+	return &schema.Resource{
+		Description: "This resource allows you to sync a local directory tree into a GitLab repository.\n\n" +
+			"All files which are created, updated or deleted to make the repository subtree match the local directory\n" +
+			"are committed in a single commit using the\n" +
+			"[GitLab Commits API](https://docs.gitlab.com/ee/api/commits.html#create-a-commit-with-multiple-files-and-actions).\n\n" +
+			"**Limitations**:\n\n" +
+			"The same limitations as for the `gitlab_repository_file` resource apply.\n" +
+			"Make sure that no other entity than the terraform at hand makes changes to the\n" +
+			"underlying repository subtree while it's executing.",
+
+		CreateContext: resourceGitlabRepositoryDirectoryCreate,
+		ReadContext:   resourceGitlabRepositoryDirectoryRead,
+		UpdateContext: resourceGitlabRepositoryDirectoryUpdate,
+		DeleteContext: resourceGitlabRepositoryDirectoryDelete,
+		CustomizeDiff: resourceGitlabRepositoryDirectoryCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Description: "The ID of the project.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"branch": {
+				Description: "Name of the branch to which to commit to.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"start_branch": {
+				Description: "Name of the branch to start the new commit from.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"source_dir": {
+				Description: "Path to the local directory which is synced into the repository.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"target_path": {
+				Description: "The path of the directory in the repository to sync the files to. It must be relative to the root of the project without a leading slash `/`. Defaults to the root of the repository.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "",
+				StateFunc: func(v interface{}) string {
+					return strings.Trim(v.(string), "/")
+				},
+			},
+			"include": {
+				Description: "Glob patterns of the files to sync, relative to `source_dir`. `*` matches any sequence of characters except `/`, `**` matches any sequence of characters including `/` and `?` matches any single character except `/`. Defaults to all files.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"exclude": {
+				Description: "Glob patterns of the files to exclude from syncing, relative to `source_dir`. Uses the same syntax as `include`.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"adopt_only": {
+				Description: "Only create, update and delete the files which exist in `source_dir`, but never delete unmanaged files from the repository subtree.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"author_email": {
+				Description: "Email of the commit author.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"author_name": {
+				Description: "Name of the commit author.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"commit_message": {
+				Description: "Commit message.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"file_hashes": {
+				Description: "Map of the managed file paths, relative to `target_path`, to the SHA256 hash of their content.",
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"blob_ids": {
+				Description: "Map of the managed file paths, relative to `target_path`, to their git blob id. Used to only read the files which changed.",
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"commit_id": {
+				Description: "The id of the last commit made to sync the directory.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceGitlabRepositoryDirectoryCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	project := d.Get("project").(string)
+	branch := d.Get("branch").(string)
+	targetPath := strings.Trim(d.Get("target_path").(string), "/")
+
+	if err := resourceGitlabRepositoryDirectorySync(ctx, client, d, nil); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(resourceGitLabRepositoryFileBuildId(project, branch, targetPath))
+	return resourceGitlabRepositoryDirectoryRead(ctx, d, meta)
+}
+
+func resourceGitlabRepositoryDirectoryRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	project, branch, targetPath, err := resourceGitLabRepositoryFileParseId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	remoteFiles, err := listRepositoryDirectoryRemoteFiles(ctx, client, project, branch, targetPath)
+	if err != nil {
+		if is404(err) {
+			log.Printf("[WARN] project %s or branch %s not found, removing repository directory %s from state", project, branch, d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	managedFiles := d.Get("file_hashes").(map[string]interface{})
+	knownBlobIDs := d.Get("blob_ids").(map[string]interface{})
+	fileHashes := make(map[string]string)
+	blobIDs := make(map[string]string)
+	for relPath, remoteFile := range remoteFiles {
+		if d.Get("adopt_only").(bool) {
+			if _, ok := managedFiles[relPath]; !ok {
+				continue
+			}
+		} else if !repositoryDirectoryPathMatches(relPath, d.Get("include").([]interface{}), d.Get("exclude").([]interface{})) {
+			continue
+		}
+
+		blobIDs[relPath] = remoteFile.ID
+
+		// The content hash is only fetched if the file changed since it was last read.
+		if hash, ok := managedFiles[relPath]; ok && knownBlobIDs[relPath] == remoteFile.ID {
+			fileHashes[relPath] = hash.(string)
+			continue
+		}
+
+		file, _, err := client.RepositoryFiles.GetFileMetaData(project, remoteFile.Path, &gitlab.GetFileMetaDataOptions{
+			Ref: gitlab.String(branch),
+		}, gitlab.WithContext(ctx))
+		if err != nil {
+			return diag.Errorf("failed to get metadata of repository file %q: %v", remoteFile.Path, err)
+		}
+		fileHashes[relPath] = file.SHA256
+	}
+
+	d.Set("project", project)
+	d.Set("branch", branch)
+	d.Set("target_path", targetPath)
+	if err := d.Set("file_hashes", fileHashes); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("blob_ids", blobIDs); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceGitlabRepositoryDirectoryUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+
+	oldFileHashes, _ := d.GetChange("file_hashes")
+	if err := resourceGitlabRepositoryDirectorySync(ctx, client, d, oldFileHashes.(map[string]interface{})); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceGitlabRepositoryDirectoryRead(ctx, d, meta)
+}
+
+func resourceGitlabRepositoryDirectoryDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	project, branch, targetPath, err := resourceGitLabRepositoryFileParseId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	remoteFiles, err := listRepositoryDirectoryRemoteFiles(ctx, client, project, branch, targetPath)
+	if err != nil {
+		if is404(err) {
+			log.Printf("[DEBUG] project %s or branch %s not found, nothing to delete", project, branch)
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	var actions []*gitlab.CommitActionOptions
+	for relPath := range d.Get("file_hashes").(map[string]interface{}) {
+		if remoteFile, ok := remoteFiles[relPath]; ok {
+			actions = append(actions, &gitlab.CommitActionOptions{
+				Action:   gitlab.FileAction(gitlab.FileDelete),
+				FilePath: gitlab.String(remoteFile.Path),
+			})
+		}
+	}
+
+	if len(actions) == 0 {
+		log.Printf("[DEBUG] no managed files left in %s, nothing to delete", d.Id())
+		return nil
+	}
+
+	options := &gitlab.CreateCommitOptions{
+		Branch:        gitlab.String(branch),
+		CommitMessage: gitlab.String(fmt.Sprintf("[DELETE]: %s", d.Get("commit_message").(string))),
+		Actions:       sortRepositoryDirectoryActions(actions),
+	}
+	if v, ok := d.GetOk("author_email"); ok {
+		options.AuthorEmail = gitlab.String(v.(string))
+	}
+	if v, ok := d.GetOk("author_name"); ok {
+		options.AuthorName = gitlab.String(v.(string))
+	}
+
+	if _, _, err := client.Commits.CreateCommit(project, options, gitlab.WithContext(ctx)); err != nil {
+		return diag.Errorf("%s failed to delete repository directory: %v", d.Id(), err)
+	}
+
+	return nil
+}
+
+func resourceGitlabRepositoryDirectoryCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("source_dir") || !d.NewValueKnown("include") || !d.NewValueKnown("exclude") {
+		for _, key := range []string{"file_hashes", "blob_ids", "commit_id"} {
+			if err := d.SetNewComputed(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	localFiles, err := listRepositoryDirectoryLocalFiles(d.Get("source_dir").(string), d.Get("include").([]interface{}), d.Get("exclude").([]interface{}))
+	if err != nil {
+		return err
+	}
+
+	localFileHashes := make(map[string]interface{}, len(localFiles))
+	for relPath, localPath := range localFiles {
+		content, err := ioutil.ReadFile(localPath)
+		if err != nil {
+			return err
+		}
+		localFileHashes[relPath] = repositoryDirectoryContentSHA256(content)
+	}
+
+	if d.Id() != "" && repositoryDirectoryFileHashesEqual(d.Get("file_hashes").(map[string]interface{}), localFileHashes) {
+		return nil
+	}
+
+	if err := d.SetNew("file_hashes", localFileHashes); err != nil {
+		return err
+	}
+	if err := d.SetNewComputed("blob_ids"); err != nil {
+		return err
+	}
+	return d.SetNewComputed("commit_id")
+}
+
+// resourceGitlabRepositoryDirectorySync makes a single commit which creates, updates and deletes
+// files, so that the repository subtree matches the local directory.
+// The managedFiles are the files which have previously been synced by the resource.
+func resourceGitlabRepositoryDirectorySync(ctx context.Context, client *gitlab.Client, d *schema.ResourceData, managedFiles map[string]interface{}) error {
+	project := d.Get("project").(string)
+	branch := d.Get("branch").(string)
+	targetPath := strings.Trim(d.Get("target_path").(string), "/")
+	include := d.Get("include").([]interface{})
+	exclude := d.Get("exclude").([]interface{})
+
+	localFiles, err := listRepositoryDirectoryLocalFiles(d.Get("source_dir").(string), include, exclude)
+	if err != nil {
+		return err
+	}
+
+	ref := branch
+	if v, ok := d.GetOk("start_branch"); ok {
+		ref = v.(string)
+	}
+	remoteFiles, err := listRepositoryDirectoryRemoteFiles(ctx, client, project, ref, targetPath)
+	if err != nil {
+		return err
+	}
+
+	var actions []*gitlab.CommitActionOptions
+	for relPath, localPath := range localFiles {
+		content, err := ioutil.ReadFile(localPath)
+		if err != nil {
+			return err
+		}
+
+		action := &gitlab.CommitActionOptions{
+			Action:   gitlab.FileAction(gitlab.FileCreate),
+			FilePath: gitlab.String(path.Join(targetPath, relPath)),
+			Content:  gitlab.String(base64.StdEncoding.EncodeToString(content)),
+			Encoding: gitlab.String(encoding),
+		}
+
+		if remoteFile, ok := remoteFiles[relPath]; ok {
+			if remoteFile.ID == repositoryDirectoryBlobID(content) {
+				continue
+			}
+			action.Action = gitlab.FileAction(gitlab.FileUpdate)
+		}
+
+		actions = append(actions, action)
+	}
+
+	for relPath, remoteFile := range remoteFiles {
+		if _, ok := localFiles[relPath]; ok {
+			continue
+		}
+
+		if d.Get("adopt_only").(bool) {
+			// Only files which have been synced before are deleted, unmanaged files are kept.
+			if _, ok := managedFiles[relPath]; !ok {
+				continue
+			}
+		} else if !repositoryDirectoryPathMatches(relPath, include, exclude) {
+			continue
+		}
+
+		actions = append(actions, &gitlab.CommitActionOptions{
+			Action:   gitlab.FileAction(gitlab.FileDelete),
+			FilePath: gitlab.String(remoteFile.Path),
+		})
+	}
+
+	if len(actions) == 0 {
+		log.Printf("[DEBUG] repository directory %q in branch %q of project %s is already in sync", targetPath, branch, project)
+		return nil
+	}
+
+	options := &gitlab.CreateCommitOptions{
+		Branch:        gitlab.String(branch),
+		CommitMessage: gitlab.String(d.Get("commit_message").(string)),
+		Actions:       sortRepositoryDirectoryActions(actions),
+	}
+	if v, ok := d.GetOk("start_branch"); ok {
+		options.StartBranch = gitlab.String(v.(string))
+	}
+	if v, ok := d.GetOk("author_email"); ok {
+		options.AuthorEmail = gitlab.String(v.(string))
+	}
+	if v, ok := d.GetOk("author_name"); ok {
+		options.AuthorName = gitlab.String(v.(string))
+	}
+
+	log.Printf("[DEBUG] sync %d files into repository directory %q in branch %q of project %s", len(actions), targetPath, branch, project)
+
+	commit, _, err := client.Commits.CreateCommit(project, options, gitlab.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	d.Set("commit_id", commit.ID)
+	return nil
+}
+
+// listRepositoryDirectoryLocalFiles returns the regular files in sourceDir which match the
+// include and exclude patterns, mapped from their slash separated path relative to sourceDir to their local path.
+func listRepositoryDirectoryLocalFiles(sourceDir string, include, exclude []interface{}) (map[string]string, error) {
+	files := make(map[string]string)
+	err := filepath.WalkDir(sourceDir, func(localPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(sourceDir, localPath)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		if repositoryDirectoryPathMatches(relPath, include, exclude) {
+			files[relPath] = localPath
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files in source directory %q: %w", sourceDir, err)
+	}
+
+	return files, nil
+}
+
+// listRepositoryDirectoryRemoteFiles returns the files in the repository below targetPath,
+// mapped from their path relative to targetPath to their tree node, which holds the full path and the blob id.
+// A missing directory has no files, while the 404 error is returned if the project or the branch is missing.
+func listRepositoryDirectoryRemoteFiles(ctx context.Context, client *gitlab.Client, project, ref, targetPath string) (map[string]*gitlab.TreeNode, error) {
+	options := &gitlab.ListTreeOptions{
+		ListOptions: gitlab.ListOptions{
+			PerPage: 100,
+			Page:    1,
+		},
+		Ref:       gitlab.String(ref),
+		Recursive: gitlab.Bool(true),
+	}
+	if targetPath != "" {
+		options.Path = gitlab.String(targetPath)
+	}
+
+	files := make(map[string]*gitlab.TreeNode)
+	for options.Page != 0 {
+		nodes, resp, err := client.Repositories.ListTree(project, options, gitlab.WithContext(ctx))
+		if err != nil {
+			if !is404(err) {
+				return nil, err
+			}
+			// The tree doesn't exist if the directory doesn't exist (yet),
+			// but a missing project or branch is reported.
+			if _, _, err := client.Branches.GetBranch(project, ref, gitlab.WithContext(ctx)); err != nil {
+				return nil, err
+			}
+			log.Printf("[DEBUG] repository tree %q at %q of project %s not found", targetPath, ref, project)
+			return files, nil
+		}
+
+		for _, node := range nodes {
+			if node.Type != "blob" {
+				continue
+			}
+			relPath := node.Path
+			if targetPath != "" {
+				relPath = strings.TrimPrefix(node.Path, targetPath+"/")
+			}
+			files[relPath] = node
+		}
+
+		options.Page = resp.NextPage
+	}
+
+	return files, nil
+}
+
+// repositoryDirectoryPathMatches checks if the slash separated relPath matches any of the include
+// patterns, or if there are none, and doesn't match any of the exclude patterns.
+func repositoryDirectoryPathMatches(relPath string, include, exclude []interface{}) bool {
+	for _, pattern := range exclude {
+		if repositoryDirectoryGlobToRegexp(pattern.(string)).MatchString(relPath) {
+			return false
+		}
+	}
+
+	if len(include) == 0 {
+		return true
+	}
+
+	for _, pattern := range include {
+		if repositoryDirectoryGlobToRegexp(pattern.(string)).MatchString(relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// repositoryDirectoryGlobToRegexp converts a glob pattern to an anchored regular expression.
+// `**/` matches zero or more directories, `**` matches anything, `*` matches anything except `/`
+// and `?` matches any single character except `/`.
+func repositoryDirectoryGlobToRegexp(pattern string) *regexp.Regexp {
+	var expr strings.Builder
+	expr.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expr.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String())
+}
+
+func repositoryDirectoryContentSHA256(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// repositoryDirectoryBlobID returns the git blob id of the content, which the tree API returns as id of its files.
+func repositoryDirectoryBlobID(content []byte) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(content))
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func repositoryDirectoryFileHashesEqual(a, b map[string]interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || v != w {
+			return false
+		}
+	}
+	return true
+}
+
+// sortRepositoryDirectoryActions sorts the commit actions by file path, so that the commit is deterministic.
+func sortRepositoryDirectoryActions(actions []*gitlab.CommitActionOptions) []*gitlab.CommitActionOptions {
+	sort.Slice(actions, func(i, j int) bool {
+		return *actions[i].FilePath < *actions[j].FilePath
+	})
+	return actions
+}
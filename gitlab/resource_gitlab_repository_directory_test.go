@@ -0,0 +1,184 @@
+package gitlab
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func TestGitlabRepositoryDirectory_pathMatches(t *testing.T) {
+	cases := []struct {
+		path     string
+		include  []interface{}
+		exclude  []interface{}
+		expected bool
+	}{
+		{path: "README.md", expected: true},
+		{path: "docs/index.md", include: []interface{}{"*.md"}, expected: false},
+		{path: "docs/index.md", include: []interface{}{"**/*.md"}, expected: true},
+		{path: "README.md", include: []interface{}{"**/*.md"}, expected: true},
+		{path: "docs/index.md", include: []interface{}{"docs/**"}, expected: true},
+		{path: "src/main.go", include: []interface{}{"docs/**"}, expected: false},
+		{path: "src/main.go", exclude: []interface{}{"**/*_test.go"}, expected: true},
+		{path: "src/main_test.go", exclude: []interface{}{"**/*_test.go"}, expected: false},
+		{path: "a.txt", include: []interface{}{"?.txt"}, expected: true},
+		{path: "ab.txt", include: []interface{}{"?.txt"}, expected: false},
+		{path: "a+b.txt", include: []interface{}{"a+b.txt"}, expected: true},
+	}
+
+	for _, c := range cases {
+		if actual := repositoryDirectoryPathMatches(c.path, c.include, c.exclude); actual != c.expected {
+			t.Errorf("path %q with include %v and exclude %v: got %t; want %t", c.path, c.include, c.exclude, actual, c.expected)
+		}
+	}
+}
+
+func TestGitlabRepositoryDirectory_blobID(t *testing.T) {
+	cases := []struct {
+		content  string
+		expected string
+	}{
+		{content: "", expected: "e69de29bb2d1d6434b8b29ae775ad8c2e48c5391"},
+		{content: "hello\n", expected: "ce013625030ba8dba906f756967f9e9ca394464a"},
+	}
+
+	for _, c := range cases {
+		if actual := repositoryDirectoryBlobID([]byte(c.content)); actual != c.expected {
+			t.Errorf("content %q: got %s; want %s", c.content, actual, c.expected)
+		}
+	}
+}
+
+func TestAccGitlabRepositoryDirectory_basic(t *testing.T) {
+	testAccCheck(t)
+
+	client := testAccNewClient(t)
+	project := testAccCreateProject(t, client)
+
+	// An unmanaged file, which is only kept if `adopt_only` is set.
+	_, _, err := client.RepositoryFiles.CreateFile(project.ID, "skeleton/unmanaged.txt", &gitlab.CreateFileOptions{
+		Branch:        gitlab.String(project.DefaultBranch),
+		Content:       gitlab.String("unmanaged"),
+		CommitMessage: gitlab.String("add unmanaged file"),
+	})
+	if err != nil {
+		t.Fatalf("could not create test file: %v", err)
+	}
+
+	sourceDir := t.TempDir()
+	testAccWriteRepositoryDirectoryFile(t, sourceDir, "README.md", "meow")
+	testAccWriteRepositoryDirectoryFile(t, sourceDir, "ci/build.yml", "build: meow")
+	testAccWriteRepositoryDirectoryFile(t, sourceDir, "ci/ignored.tmp", "ignored")
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabRepositoryDirectoryDestroy(client, project),
+		Steps: []resource.TestStep{
+			// Create with adopt only, which keeps the unmanaged file
+			{
+				Config: testAccGitlabRepositoryDirectoryConfig(project, sourceDir, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlab_repository_directory.this", "file_hashes.%", "2"),
+					resource.TestCheckResourceAttrSet("gitlab_repository_directory.this", "file_hashes.README.md"),
+					resource.TestCheckResourceAttr("gitlab_repository_directory.this", "blob_ids.%", "2"),
+					resource.TestCheckResourceAttrSet("gitlab_repository_directory.this", "file_hashes.ci/build.yml"),
+					resource.TestCheckResourceAttrSet("gitlab_repository_directory.this", "commit_id"),
+					testAccCheckGitlabRepositoryDirectoryFiles(client, project, map[string]bool{
+						"skeleton/README.md":      true,
+						"skeleton/ci/build.yml":   true,
+						"skeleton/ci/ignored.tmp": false,
+						"skeleton/unmanaged.txt":  true,
+					}),
+				),
+			},
+			// Update a local file and remove another one
+			{
+				PreConfig: func() {
+					testAccWriteRepositoryDirectoryFile(t, sourceDir, "README.md", "meow meow")
+					if err := os.Remove(filepath.Join(sourceDir, "ci", "build.yml")); err != nil {
+						t.Fatalf("could not remove local test file: %v", err)
+					}
+				},
+				Config: testAccGitlabRepositoryDirectoryConfig(project, sourceDir, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlab_repository_directory.this", "file_hashes.%", "1"),
+					testAccCheckGitlabRepositoryDirectoryFiles(client, project, map[string]bool{
+						"skeleton/README.md":     true,
+						"skeleton/ci/build.yml":  false,
+						"skeleton/unmanaged.txt": true,
+					}),
+				),
+			},
+			// Without adopt only, unmanaged files are deleted
+			{
+				Config: testAccGitlabRepositoryDirectoryConfig(project, sourceDir, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlab_repository_directory.this", "file_hashes.%", "1"),
+					testAccCheckGitlabRepositoryDirectoryFiles(client, project, map[string]bool{
+						"skeleton/README.md":     true,
+						"skeleton/unmanaged.txt": false,
+					}),
+				),
+			},
+		},
+	})
+}
+
+func testAccWriteRepositoryDirectoryFile(t *testing.T, sourceDir, relPath, content string) {
+	t.Helper()
+
+	localPath := filepath.Join(sourceDir, filepath.FromSlash(relPath))
+	if err := os.MkdirAll(filepath.Dir(localPath), 0o755); err != nil {
+		t.Fatalf("could not create local test directory: %v", err)
+	}
+	if err := ioutil.WriteFile(localPath, []byte(content), 0o600); err != nil {
+		t.Fatalf("could not write local test file: %v", err)
+	}
+}
+
+func testAccCheckGitlabRepositoryDirectoryFiles(client *gitlab.Client, project *gitlab.Project, expected map[string]bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for filePath, shouldExist := range expected {
+			_, _, err := client.RepositoryFiles.GetFileMetaData(project.ID, filePath, &gitlab.GetFileMetaDataOptions{
+				Ref: gitlab.String(project.DefaultBranch),
+			})
+			if err != nil && !is404(err) {
+				return err
+			}
+			if exists := err == nil; exists != shouldExist {
+				return fmt.Errorf("repository file %q exists: %t; want %t", filePath, exists, shouldExist)
+			}
+		}
+		return nil
+	}
+}
+
+func testAccCheckGitlabRepositoryDirectoryDestroy(client *gitlab.Client, project *gitlab.Project) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		return testAccCheckGitlabRepositoryDirectoryFiles(client, project, map[string]bool{
+			"skeleton/README.md": false,
+		})(s)
+	}
+}
+
+func testAccGitlabRepositoryDirectoryConfig(project *gitlab.Project, sourceDir string, adoptOnly bool) string {
+	return fmt.Sprintf(`
+resource "gitlab_repository_directory" "this" {
+  project        = %d
+  branch         = %q
+  source_dir     = %q
+  target_path    = "skeleton"
+  exclude        = ["**/*.tmp"]
+  adopt_only     = %t
+  author_email   = "meow@catnip.com"
+  author_name    = "Meow Meowington"
+  commit_message = "feature: sync skeleton"
+}
+	`, project.ID, project.DefaultBranch, sourceDir, adoptOnly)
+}
@@ -2,16 +2,21 @@ package gitlab
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"log"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	gitlab "github.com/xanzy/go-gitlab"
 )
 
+// encoding is the encoding used to transfer the file content to and from the GitLab API.
+// Content configured with the `text` encoding is converted locally.
 const encoding = "base64"
 
 func resourceGitLabRepositoryFile() *schema.Resource {
@@ -35,11 +40,13 @@ func resourceGitLabRepositoryFile() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		CustomizeDiff: resourceGitlabRepositoryFileCustomizeDiff,
 
 		// the schema matches https://docs.gitlab.com/ee/api/repository_files.html#create-new-file-in-repository
-		// However, we don't support the `encoding` parameter as it seems to be broken.
+		// However, we don't pass the `encoding` parameter through to the API as it seems to be broken.
 		// Only a value of `base64` is supported, all others, including the documented default `text`, lead to
 		// a `400 {error: encoding does not have a valid value}` error.
+		// Content with the `text` encoding is therefore base64 encoded by the provider before it is sent.
 		Schema: map[string]*schema.Schema{
 			"project": {
 				Description: "The ID of the project.",
@@ -75,10 +82,10 @@ func resourceGitLabRepositoryFile() *schema.Resource {
 				Optional:    true,
 			},
 			"content": {
-				Description:  "base64 encoded file content. No other encoding is currently supported, because of a [GitLab API bug](https://gitlab.com/gitlab-org/gitlab/-/issues/342430).",
-				Type:         schema.TypeString,
-				Required:     true,
-				ValidateFunc: validateBase64Content,
+				Description:      "File content. Must be base64 encoded if `encoding` is `base64`, which is the default.",
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: resourceGitlabRepositoryFileContentDiffSuppress,
 			},
 			"commit_message": {
 				Description: "Commit message.",
@@ -86,7 +93,41 @@ func resourceGitLabRepositoryFile() *schema.Resource {
 				Required:    true,
 			},
 			"encoding": {
-				Description: "Content encoding.",
+				Description:  "Content encoding. Valid values are `base64` and `text`. Content with the `text` encoding is base64 encoded by the provider, because of a [GitLab API bug](https://gitlab.com/gitlab-org/gitlab/-/issues/342430).",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "base64",
+				ValidateFunc: validation.StringInSlice([]string{"base64", "text"}, false),
+			},
+			"store_content_hash_only": {
+				Description: "Don't read the content of the file back into the state, but detect changes of the content by comparing the hash of the configured content with `content_sha256`. This is useful for large files.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"overwrite_on_create": {
+				Description: "Adopt and overwrite the file if it already exists in the branch when the resource is created, instead of failing.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"content_sha256": {
+				Description: "The SHA256 hash of the (decoded) file content.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"blob_id": {
+				Description: "The blob id of the file.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"commit_id": {
+				Description: "The commit id of the branch head the file was read from.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"last_commit_id": {
+				Description: "The id of the last commit that modified the file. Used to detect concurrent changes to the file when it is updated.",
 				Type:        schema.TypeString,
 				Computed:    true,
 			},
@@ -98,13 +139,52 @@ func resourceGitlabRepositoryFileCreate(ctx context.Context, d *schema.ResourceD
 	client := meta.(*gitlab.Client)
 	project := d.Get("project").(string)
 	filePath := d.Get("file_path").(string)
+	branch := d.Get("branch").(string)
+
+	content, err := resourceGitlabRepositoryFileEncodeContent(d.Get("content").(string), d.Get("encoding").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.Get("overwrite_on_create").(bool) {
+		existingRepositoryFile, _, err := client.RepositoryFiles.GetFile(project, filePath, &gitlab.GetFileOptions{
+			Ref: gitlab.String(branch),
+		}, gitlab.WithContext(ctx))
+		if err != nil && !is404(err) {
+			return diag.FromErr(err)
+		}
+
+		if existingRepositoryFile != nil {
+			log.Printf("[DEBUG] file %s already exists in branch %s of project %s, overwriting it", filePath, branch, project)
+			options := &gitlab.UpdateFileOptions{
+				Branch:        gitlab.String(branch),
+				Encoding:      gitlab.String(encoding),
+				AuthorEmail:   gitlab.String(d.Get("author_email").(string)),
+				AuthorName:    gitlab.String(d.Get("author_name").(string)),
+				Content:       gitlab.String(content),
+				CommitMessage: gitlab.String(d.Get("commit_message").(string)),
+				LastCommitID:  gitlab.String(existingRepositoryFile.LastCommitID),
+			}
+			if startBranch, ok := d.GetOk("start_branch"); ok {
+				options.StartBranch = gitlab.String(startBranch.(string))
+			}
+
+			repositoryFile, _, err := client.RepositoryFiles.UpdateFile(project, filePath, options, gitlab.WithContext(ctx))
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
+			d.SetId(resourceGitLabRepositoryFileBuildId(project, repositoryFile.Branch, repositoryFile.FilePath))
+			return resourceGitlabRepositoryFileRead(ctx, d, meta)
+		}
+	}
 
 	options := &gitlab.CreateFileOptions{
-		Branch:        gitlab.String(d.Get("branch").(string)),
+		Branch:        gitlab.String(branch),
 		Encoding:      gitlab.String(encoding),
 		AuthorEmail:   gitlab.String(d.Get("author_email").(string)),
 		AuthorName:    gitlab.String(d.Get("author_name").(string)),
-		Content:       gitlab.String(d.Get("content").(string)),
+		Content:       gitlab.String(content),
 		CommitMessage: gitlab.String(d.Get("commit_message").(string)),
 	}
 	if startBranch, ok := d.GetOk("start_branch"); ok {
@@ -141,12 +221,30 @@ func resourceGitlabRepositoryFileRead(ctx context.Context, d *schema.ResourceDat
 		return diag.FromErr(err)
 	}
 
+	// the encoding is not known when the resource is imported
+	fileEncoding, ok := d.GetOk("encoding")
+	if !ok {
+		fileEncoding = repositoryFile.Encoding
+	}
+
+	content, err := resourceGitlabRepositoryFileDecodeContent(repositoryFile.Content, fileEncoding.(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	d.SetId(resourceGitLabRepositoryFileBuildId(project, branch, repositoryFile.FilePath))
 	d.Set("project", project)
 	d.Set("file_path", repositoryFile.FilePath)
 	d.Set("branch", repositoryFile.Ref)
-	d.Set("encoding", repositoryFile.Encoding)
-	d.Set("content", repositoryFile.Content)
+	d.Set("encoding", fileEncoding)
+	// Keep the configured content, changes are detected by comparing its hash with the one of the file.
+	if !d.Get("store_content_hash_only").(bool) {
+		d.Set("content", content)
+	}
+	d.Set("content_sha256", repositoryFile.SHA256)
+	d.Set("blob_id", repositoryFile.BlobID)
+	d.Set("commit_id", repositoryFile.CommitID)
+	d.Set("last_commit_id", repositoryFile.LastCommitID)
 
 	return nil
 }
@@ -158,23 +256,28 @@ func resourceGitlabRepositoryFileUpdate(ctx context.Context, d *schema.ResourceD
 		return diag.FromErr(err)
 	}
 
-	readOptions := &gitlab.GetFileOptions{
-		Ref: gitlab.String(branch),
+	// these attributes only affect how the file is managed by the provider and don't require a new commit.
+	if !d.HasChangesExcept("store_content_hash_only", "overwrite_on_create") {
+		return resourceGitlabRepositoryFileRead(ctx, d, meta)
 	}
 
-	existingRepositoryFile, _, err := client.RepositoryFiles.GetFile(project, filePath, readOptions, gitlab.WithContext(ctx))
+	content, err := resourceGitlabRepositoryFileEncodeContent(d.Get("content").(string), d.Get("encoding").(string))
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	// The last commit id from the state is sent along, so that the update fails
+	// if the file has been changed since it was last read.
+	// The planned value is unknown whenever the content changes, so the prior one is used.
+	lastCommitID, _ := d.GetChange("last_commit_id")
 	options := &gitlab.UpdateFileOptions{
 		Branch:        gitlab.String(branch),
 		Encoding:      gitlab.String(encoding),
 		AuthorEmail:   gitlab.String(d.Get("author_email").(string)),
 		AuthorName:    gitlab.String(d.Get("author_name").(string)),
-		Content:       gitlab.String(d.Get("content").(string)),
+		Content:       gitlab.String(content),
 		CommitMessage: gitlab.String(d.Get("commit_message").(string)),
-		LastCommitID:  gitlab.String(existingRepositoryFile.LastCommitID),
+		LastCommitID:  gitlab.String(lastCommitID.(string)),
 	}
 	if startBranch, ok := d.GetOk("start_branch"); ok {
 		options.StartBranch = gitlab.String(startBranch.(string))
@@ -220,6 +323,95 @@ func resourceGitlabRepositoryFileDelete(ctx context.Context, d *schema.ResourceD
 	return nil
 }
 
+func resourceGitlabRepositoryFileCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.NewValueKnown("content") && d.Get("encoding").(string) == "base64" {
+		if _, errs := validateBase64Content(d.Get("content"), "content"); len(errs) > 0 {
+			return errs[0]
+		}
+	}
+
+	changed := d.HasChange("content") || d.HasChange("encoding")
+
+	// The content in the state isn't refreshed if only its hash is compared,
+	// so a changed file is detected by comparing the hashes.
+	if !changed && d.Id() != "" && d.Get("store_content_hash_only").(bool) && d.NewValueKnown("content") {
+		sha, err := resourceGitlabRepositoryFileContentSHA256(d.Get("content").(string), d.Get("encoding").(string))
+		if err != nil {
+			return err
+		}
+		changed = sha != d.Get("content_sha256").(string)
+	}
+
+	if changed {
+		for _, key := range []string{"content_sha256", "blob_id", "commit_id", "last_commit_id"} {
+			if err := d.SetNewComputed(key); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// resourceGitlabRepositoryFileContentDiffSuppress suppresses the content diff if the content isn't read
+// back into the state and the hash of the configured content matches the one of the file.
+func resourceGitlabRepositoryFileContentDiffSuppress(k, old, new string, d *schema.ResourceData) bool {
+	if old == new {
+		return true
+	}
+	if !d.Get("store_content_hash_only").(bool) || d.Id() == "" {
+		return false
+	}
+
+	sha, err := resourceGitlabRepositoryFileContentSHA256(new, d.Get("encoding").(string))
+	if err != nil {
+		return false
+	}
+	return sha == d.Get("content_sha256").(string)
+}
+
+// resourceGitlabRepositoryFileEncodeContent converts the configured content into the base64 encoding used by the API.
+func resourceGitlabRepositoryFileEncodeContent(content string, contentEncoding string) (string, error) {
+	switch contentEncoding {
+	case "base64":
+		return content, nil
+	case "text":
+		return base64.StdEncoding.EncodeToString([]byte(content)), nil
+	}
+	return "", fmt.Errorf("unsupported repository file encoding %q", contentEncoding)
+}
+
+// resourceGitlabRepositoryFileDecodeContent converts base64 encoded content from the API into the configured encoding.
+func resourceGitlabRepositoryFileDecodeContent(content string, contentEncoding string) (string, error) {
+	switch contentEncoding {
+	case "base64":
+		return content, nil
+	case "text":
+		decoded, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode repository file content: %w", err)
+		}
+		return string(decoded), nil
+	}
+	return "", fmt.Errorf("unsupported repository file encoding %q", contentEncoding)
+}
+
+// resourceGitlabRepositoryFileContentSHA256 computes the SHA256 hash of the raw content,
+// in the same format as the `content_sha256` returned by the API.
+func resourceGitlabRepositoryFileContentSHA256(content string, contentEncoding string) (string, error) {
+	raw := []byte(content)
+	if contentEncoding == "base64" {
+		decoded, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			return "", err
+		}
+		raw = decoded
+	}
+
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
+
 func validateBase64Content(v interface{}, k string) (we []string, errors []error) {
 	content := v.(string)
 	if _, err := base64.StdEncoding.DecodeString(content); err != nil {
@@ -85,6 +85,138 @@ func TestAccGitlabRepositoryFile_validationOfBase64Content(t *testing.T) {
 	}
 }
 
+func TestGitlabRepositoryFile_contentEncoding(t *testing.T) {
+	cases := []struct {
+		content        string
+		encoding       string
+		expectedBase64 string
+		expectedSHA256 string
+	}{
+		{
+			content:        "bWVvdyBtZW93IG1lb3c=",
+			encoding:       "base64",
+			expectedBase64: "bWVvdyBtZW93IG1lb3c=",
+			expectedSHA256: "9161b95bd541e0cf52a8e6dfced85fd1ca765120e74eff997b9045b9a206f74c",
+		},
+		{
+			content:        "meow meow meow",
+			encoding:       "text",
+			expectedBase64: "bWVvdyBtZW93IG1lb3c=",
+			expectedSHA256: "9161b95bd541e0cf52a8e6dfced85fd1ca765120e74eff997b9045b9a206f74c",
+		},
+	}
+
+	for _, c := range cases {
+		encoded, err := resourceGitlabRepositoryFileEncodeContent(c.content, c.encoding)
+		if err != nil {
+			t.Fatalf("failed to encode %q content %q: %v", c.encoding, c.content, err)
+		}
+		if encoded != c.expectedBase64 {
+			t.Fatalf("got encoded content %q; want %q", encoded, c.expectedBase64)
+		}
+
+		decoded, err := resourceGitlabRepositoryFileDecodeContent(encoded, c.encoding)
+		if err != nil {
+			t.Fatalf("failed to decode %q content %q: %v", c.encoding, encoded, err)
+		}
+		if decoded != c.content {
+			t.Fatalf("got decoded content %q; want %q", decoded, c.content)
+		}
+
+		sha, err := resourceGitlabRepositoryFileContentSHA256(c.content, c.encoding)
+		if err != nil {
+			t.Fatalf("failed to hash %q content %q: %v", c.encoding, c.content, err)
+		}
+		if sha != c.expectedSHA256 {
+			t.Fatalf("got content hash %q; want %q", sha, c.expectedSHA256)
+		}
+	}
+}
+
+func TestAccGitlabRepositoryFile_textEncoding(t *testing.T) {
+	var file gitlab.File
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabRepositoryFileDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGitlabRepositoryFileTextEncodingConfig(rInt, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabRepositoryFileExists("gitlab_repository_file.this", &file),
+					testAccCheckGitlabRepositoryFileAttributes(&file, &testAccGitlabRepositoryFileAttributes{
+						FilePath: "meow.txt",
+						Content:  "bWVvdyBtZW93IG1lb3c=",
+					}),
+					resource.TestCheckResourceAttr("gitlab_repository_file.this", "content", "meow meow meow"),
+					resource.TestCheckResourceAttr("gitlab_repository_file.this", "content_sha256", "9161b95bd541e0cf52a8e6dfced85fd1ca765120e74eff997b9045b9a206f74c"),
+					resource.TestCheckResourceAttrSet("gitlab_repository_file.this", "blob_id"),
+					resource.TestCheckResourceAttrSet("gitlab_repository_file.this", "commit_id"),
+					resource.TestCheckResourceAttrSet("gitlab_repository_file.this", "last_commit_id"),
+				),
+			},
+			{
+				Config: testAccGitlabRepositoryFileTextEncodingConfig(rInt, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlab_repository_file.this", "content", "meow meow meow"),
+					resource.TestCheckResourceAttr("gitlab_repository_file.this", "content_sha256", "9161b95bd541e0cf52a8e6dfced85fd1ca765120e74eff997b9045b9a206f74c"),
+				),
+			},
+			// Re-apply the same configuration to make sure that the hash only state doesn't produce a diff.
+			{
+				Config:   testAccGitlabRepositoryFileTextEncodingConfig(rInt, true),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccGitlabRepositoryFile_overwriteOnCreate(t *testing.T) {
+	testAccCheck(t)
+
+	client := testAccNewClient(t)
+	project := testAccCreateProject(t, client)
+
+	_, _, err := client.RepositoryFiles.CreateFile(project.ID, "meow.txt", &gitlab.CreateFileOptions{
+		Branch:        gitlab.String(project.DefaultBranch),
+		Content:       gitlab.String("existing content"),
+		CommitMessage: gitlab.String("add existing file"),
+	})
+	if err != nil {
+		t.Fatalf("could not create test file: %v", err)
+	}
+
+	var file gitlab.File
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabRepositoryFileDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "gitlab_repository_file" "this" {
+  project             = %d
+  file_path           = "meow.txt"
+  branch              = %q
+  content             = "bWVvdyBtZW93IG1lb3c="
+  commit_message      = "feature: adopt launch codes"
+  overwrite_on_create = true
+}
+				`, project.ID, project.DefaultBranch),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabRepositoryFileExists("gitlab_repository_file.this", &file),
+					testAccCheckGitlabRepositoryFileAttributes(&file, &testAccGitlabRepositoryFileAttributes{
+						FilePath: "meow.txt",
+						Content:  "bWVvdyBtZW93IG1lb3c=",
+					}),
+				),
+			},
+		},
+	})
+}
+
 func TestAccGitlabRepositoryFile_createOnNewBranch(t *testing.T) {
 	var file gitlab.File
 	rInt := acctest.RandInt()
@@ -158,7 +290,7 @@ func TestAccGitlabRepositoryFile_import(t *testing.T) {
 				ResourceName:            resourceName,
 				ImportState:             true,
 				ImportStateVerify:       true,
-				ImportStateVerifyIgnore: []string{"author_email", "author_name", "commit_message"},
+				ImportStateVerifyIgnore: []string{"author_email", "author_name", "commit_message", "overwrite_on_create", "store_content_hash_only"},
 			},
 		},
 	})
@@ -370,3 +502,31 @@ resource "gitlab_repository_file" "bar_file" {
 }
 	`, rInt, rInt)
 }
+
+func testAccGitlabRepositoryFileTextEncodingConfig(rInt int, storeContentHashOnly bool) string {
+	return fmt.Sprintf(`
+resource "gitlab_project" "foo" {
+  name = "foo-%d"
+  description = "Terraform acceptance tests"
+
+  default_branch = "main"
+
+  # So that acceptance tests can be run in a gitlab organization
+  # with no billing
+  visibility_level = "public"
+  initialize_with_readme = true
+}
+
+resource "gitlab_repository_file" "this" {
+  project = "${gitlab_project.foo.id}"
+  file_path = "meow.txt"
+  branch = "main"
+  encoding = "text"
+  content = "meow meow meow"
+  store_content_hash_only = %t
+  author_email = "meow@catnip.com"
+  author_name = "Meow Meowington"
+  commit_message = "feature: add launch codes"
+}
+	`, rInt, storeContentHashOnly)
+}
@@ -0,0 +1,139 @@
+package gitlab
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func resourceGitlabTag() *schema.Resource {
+	return &schema.Resource{
+		Description: "This resource allows you to create and manage tags of a project repository. " +
+			"A lightweight tag is created, unless a `message` is given, which creates an annotated tag.",
+
+		CreateContext: resourceGitlabTagCreate,
+		ReadContext:   resourceGitlabTagRead,
+		DeleteContext: resourceGitlabTagDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Description: "The ID or full path of the project.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"name": {
+				Description: "The name of the tag.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"ref": {
+				Description: "The branch name or commit SHA to create the tag from. It is only used during creation and is not read back, so it is ignored for imported tags.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					// The ref is unknown for imported tags
+					return old == "" && d.Id() != ""
+				},
+			},
+			"message": {
+				Description: "The message of an annotated tag. A lightweight tag is created if it is not set.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"commit_sha": {
+				Description: "The SHA of the commit the tag points to.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceGitlabTagCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	project := d.Get("project").(string)
+
+	options := &gitlab.CreateTagOptions{
+		TagName: gitlab.String(d.Get("name").(string)),
+		Ref:     gitlab.String(d.Get("ref").(string)),
+	}
+	if v, ok := d.GetOk("message"); ok {
+		options.Message = gitlab.String(v.(string))
+	}
+
+	log.Printf("[DEBUG] create gitlab tag %q from %q for project %s", *options.TagName, *options.Ref, project)
+
+	tag, _, err := client.Tags.CreateTag(project, options, gitlab.WithContext(ctx))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(buildTwoPartID(&project, &tag.Name))
+
+	return resourceGitlabTagRead(ctx, d, meta)
+}
+
+func resourceGitlabTagRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	project, name, err := parseTwoPartID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] read gitlab tag %q for project %s", name, project)
+
+	tag, _, err := client.Tags.GetTag(project, name, gitlab.WithContext(ctx))
+	if err != nil {
+		if is404(err) {
+			log.Printf("[DEBUG] gitlab tag %q for project %s not found, removing from state", name, project)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	d.Set("project", project)
+	for k, v := range flattenGitlabTag(tag) {
+		d.Set(k, v)
+	}
+
+	return nil
+}
+
+func resourceGitlabTagDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*gitlab.Client)
+	project, name, err := parseTwoPartID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] delete gitlab tag %q for project %s", name, project)
+
+	if _, err := client.Tags.DeleteTag(project, name, gitlab.WithContext(ctx)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func flattenGitlabTag(tag *gitlab.Tag) map[string]interface{} {
+	values := map[string]interface{}{
+		"name":       tag.Name,
+		"message":    tag.Message,
+		"commit_sha": "",
+	}
+	if tag.Commit != nil {
+		values["commit_sha"] = tag.Commit.ID
+	}
+	return values
+}
@@ -2,13 +2,69 @@ package gitlab
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	gitlab "github.com/xanzy/go-gitlab"
 )
 
+var allowedToCreateElem = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"access_level": {
+			Description: "Level of access.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"access_level_description": {
+			Description: "Readable description of level of access.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"user_id": {
+			Description: "The ID of a GitLab user allowed to create matching tags. Mutually exclusive with `group_id` and `deploy_key_id`.",
+			Type:        schema.TypeInt,
+			Optional:    true,
+			ForceNew:    true,
+		},
+		"group_id": {
+			Description: "The ID of a GitLab group allowed to create matching tags. Mutually exclusive with `user_id` and `deploy_key_id`.",
+			Type:        schema.TypeInt,
+			Optional:    true,
+			ForceNew:    true,
+		},
+		"deploy_key_id": {
+			Description: "The ID of a GitLab deploy key allowed to create matching tags. Mutually exclusive with `user_id` and `group_id`.",
+			Type:        schema.TypeInt,
+			Optional:    true,
+			ForceNew:    true,
+		},
+	},
+}
+
+// gitlabTagAccessDescription extends the tag access description of go-gitlab with the user, group and deploy key.
+type gitlabTagAccessDescription struct {
+	gitlab.TagAccessDescription
+	ID          int `json:"id"`
+	UserID      int `json:"user_id"`
+	GroupID     int `json:"group_id"`
+	DeployKeyID int `json:"deploy_key_id"`
+}
+
+// gitlabProtectedTag mirrors the protected tag of go-gitlab with the extended access descriptions.
+type gitlabProtectedTag struct {
+	Name               string                        `json:"name"`
+	CreateAccessLevels []*gitlabTagAccessDescription `json:"create_access_levels"`
+}
+
+// gitlabProtectRepositoryTagsOptions extends the options of go-gitlab with allowed_to_create.
+type gitlabProtectRepositoryTagsOptions struct {
+	gitlab.ProtectRepositoryTagsOptions
+	AllowedToCreate []*gitlab.BranchPermissionOptions `json:"allowed_to_create,omitempty"`
+}
+
 func resourceGitlabTagProtection() *schema.Resource {
 	acceptedAccessLevels := make([]string, 0, len(tagProtectionAccessLevelID))
 
@@ -16,7 +72,8 @@ func resourceGitlabTagProtection() *schema.Resource {
 		acceptedAccessLevels = append(acceptedAccessLevels, k)
 	}
 	return &schema.Resource{
-		Description: "This resource allows you to protect a specific tag or wildcard by an access level so that the user with less access level cannot Create the tags.",
+		Description: "This resource allows you to protect a specific tag or wildcard by an access level so that the user with less access level cannot Create the tags.\n\n" +
+			"-> The `allowed_to_create` argument requires a GitLab Premium account or above.  Please refer to [Gitlab API documentation](https://docs.gitlab.com/ee/api/protected_tags.html) for further information.",
 
 		CreateContext: resourceGitlabTagProtectionCreate,
 		ReadContext:   resourceGitlabTagProtectionRead,
@@ -45,6 +102,13 @@ func resourceGitlabTagProtection() *schema.Resource {
 				Required:         true,
 				ForceNew:         true,
 			},
+			"allowed_to_create": {
+				Description: "Users, groups or deploy keys which are allowed to create matching tags in addition to the `create_access_level`.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        allowedToCreateElem,
+			},
 		},
 	}
 }
@@ -55,14 +119,30 @@ func resourceGitlabTagProtectionCreate(ctx context.Context, d *schema.ResourceDa
 	tag := gitlab.String(d.Get("tag").(string))
 	createAccessLevel := tagProtectionAccessLevelID[d.Get("create_access_level").(string)]
 
-	options := &gitlab.ProtectRepositoryTagsOptions{
-		Name:              tag,
-		CreateAccessLevel: &createAccessLevel,
+	allowedToCreate := expandBranchPermissionOptions(d.Get("allowed_to_create").(*schema.Set).List())
+
+	// The feature is checked before the tag is protected, so that no protection is left behind outside of the state.
+	if len(allowedToCreate) > 0 {
+		version, err := getGitlabVersion(ctx, client)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if version.isCommunityEdition() {
+			return diag.Errorf("feature unavailable: allowed_to_create requires GitLab Enterprise Edition")
+		}
+	}
+
+	options := &gitlabProtectRepositoryTagsOptions{
+		ProtectRepositoryTagsOptions: gitlab.ProtectRepositoryTagsOptions{
+			Name:              tag,
+			CreateAccessLevel: &createAccessLevel,
+		},
+		AllowedToCreate: allowedToCreate,
 	}
 
-	log.Printf("[DEBUG] create gitlab tag protection on %v for project %s", options.Name, project)
+	log.Printf("[DEBUG] create gitlab tag protection on %v for project %s", *options.Name, project)
 
-	tp, _, err := client.ProtectedTags.ProtectRepositoryTags(project, options, gitlab.WithContext(ctx))
+	tp, err := protectGitlabRepositoryTags(ctx, client, project, options)
 	if err != nil {
 		// Remove existing tag protection
 		_, err = client.ProtectedTags.UnprotectRepositoryTags(project, *tag, gitlab.WithContext(ctx))
@@ -70,14 +150,21 @@ func resourceGitlabTagProtectionCreate(ctx context.Context, d *schema.ResourceDa
 			return diag.FromErr(err)
 		}
 		// Reprotect tag with updated values
-		tp, _, err = client.ProtectedTags.ProtectRepositoryTags(project, options, gitlab.WithContext(ctx))
+		tp, err = protectGitlabRepositoryTags(ctx, client, project, options)
 		if err != nil {
 			return diag.FromErr(err)
 		}
 	}
 
+	// from this point onwards no matter how we return, resource creation
+	// is committed to state since we set its ID
 	d.SetId(buildTwoPartID(&project, &tp.Name))
 
+	// The license of an Enterprise Edition instance may still not include the feature.
+	if len(allowedToCreate) > 0 && len(convertAllowedToCreateToTagAccessDescriptions(tp.CreateAccessLevels)) == 0 {
+		return diag.Errorf("feature unavailable: allowed_to_create")
+	}
+
 	return resourceGitlabTagProtectionRead(ctx, d, meta)
 }
 
@@ -90,7 +177,7 @@ func resourceGitlabTagProtectionRead(ctx context.Context, d *schema.ResourceData
 
 	log.Printf("[DEBUG] read gitlab tag protection for project %s, tag %s", project, tag)
 
-	pt, _, err := client.ProtectedTags.GetProtectedTag(project, tag, gitlab.WithContext(ctx))
+	pt, err := getGitlabProtectedTag(ctx, client, project, tag)
 	if err != nil {
 		if is404(err) {
 			log.Printf("[DEBUG] gitlab tag protection not found %s/%s", project, tag)
@@ -100,14 +187,18 @@ func resourceGitlabTagProtectionRead(ctx context.Context, d *schema.ResourceData
 		return diag.FromErr(err)
 	}
 
-	accessLevel, ok := tagProtectionAccessLevelNames[pt.CreateAccessLevels[0].AccessLevel]
-	if !ok {
-		return diag.Errorf("tag protection access level %d is not supported. Supported are: %v", pt.CreateAccessLevels[0].AccessLevel, tagProtectionAccessLevelNames)
+	createAccessLevel, err := tagProtectionCreateAccessLevel(pt)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	d.Set("project", project)
 	d.Set("tag", pt.Name)
-	d.Set("create_access_level", accessLevel)
+	d.Set("create_access_level", createAccessLevel)
+
+	if err := d.Set("allowed_to_create", convertAllowedToCreateToTagAccessDescriptions(pt.CreateAccessLevels)); err != nil {
+		return diag.Errorf("error setting allowed_to_create: %v", err)
+	}
 
 	d.SetId(buildTwoPartID(&project, &pt.Name))
 
@@ -137,3 +228,97 @@ func projectAndTagFromID(id string) (string, string, error) {
 	}
 	return project, tag, err
 }
+
+// tagProtectionCreateAccessLevel returns the name of the role based create access level of the protected tag.
+func tagProtectionCreateAccessLevel(pt *gitlabProtectedTag) (string, error) {
+	for _, description := range pt.CreateAccessLevels {
+		if description.UserID != 0 || description.GroupID != 0 || description.DeployKeyID != 0 {
+			continue
+		}
+
+		accessLevel, ok := tagProtectionAccessLevelNames[description.AccessLevel]
+		if !ok {
+			return "", fmt.Errorf("tag protection access level %d is not supported. Supported are: %v", description.AccessLevel, tagProtectionAccessLevelNames)
+		}
+		return accessLevel, nil
+	}
+
+	return "", fmt.Errorf("tag protection %q has no create access level", pt.Name)
+}
+
+func convertAllowedToCreateToTagAccessDescriptions(descriptions []*gitlabTagAccessDescription) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0)
+
+	for _, description := range descriptions {
+		if description.UserID == 0 && description.GroupID == 0 && description.DeployKeyID == 0 {
+			continue
+		}
+		result = append(result, map[string]interface{}{
+			"access_level":             accessLevel[description.AccessLevel],
+			"access_level_description": description.AccessLevelDescription,
+			"user_id":                  description.UserID,
+			"group_id":                 description.GroupID,
+			"deploy_key_id":            description.DeployKeyID,
+		})
+	}
+
+	return result
+}
+
+func getGitlabProtectedTag(ctx context.Context, client *gitlab.Client, project string, tag string) (*gitlabProtectedTag, error) {
+	// go-gitlab doesn't expose the users, groups and deploy keys of the create access levels
+	u := fmt.Sprintf("projects/%s/protected_tags/%s", gitlab.PathEscape(project), gitlab.PathEscape(tag))
+
+	req, err := client.NewRequest(http.MethodGet, u, nil, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+
+	pt := new(gitlabProtectedTag)
+	if _, err := client.Do(req, pt); err != nil {
+		return nil, err
+	}
+
+	return pt, nil
+}
+
+func protectGitlabRepositoryTags(ctx context.Context, client *gitlab.Client, project string, options *gitlabProtectRepositoryTagsOptions) (*gitlabProtectedTag, error) {
+	u := fmt.Sprintf("projects/%s/protected_tags", gitlab.PathEscape(project))
+
+	req, err := client.NewRequest(http.MethodPost, u, options, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+
+	pt := new(gitlabProtectedTag)
+	if _, err := client.Do(req, pt); err != nil {
+		return nil, err
+	}
+
+	return pt, nil
+}
+
+func listGitlabProtectedTags(ctx context.Context, client *gitlab.Client, project string) ([]*gitlabProtectedTag, error) {
+	u := fmt.Sprintf("projects/%s/protected_tags", gitlab.PathEscape(project))
+
+	protectedTags := make([]*gitlabProtectedTag, 0)
+
+	options := &gitlab.ListProtectedTagsOptions{PerPage: 100, Page: 1}
+	for options.Page != 0 {
+		req, err := client.NewRequest(http.MethodGet, u, options, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+		if err != nil {
+			return nil, err
+		}
+
+		var pts []*gitlabProtectedTag
+		resp, err := client.Do(req, &pts)
+		if err != nil {
+			return nil, err
+		}
+
+		protectedTags = append(protectedTags, pts...)
+		options.Page = resp.NextPage
+	}
+
+	return protectedTags, nil
+}
@@ -106,6 +106,35 @@ func TestAccGitlabTagProtection_wildcard(t *testing.T) {
 	})
 }
 
+func TestAccGitlabTagProtection_allowedToCreate(t *testing.T) {
+	var pt gitlab.ProtectedTag
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabTagProtectionDestroy,
+		Steps: []resource.TestStep{
+			{
+				SkipFunc: isRunningInCE,
+				Config:   testAccGitlabTagProtectionConfigAllowedToCreate(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabTagProtectionExists("gitlab_tag_protection.TagProtect", &pt),
+					resource.TestCheckResourceAttr("gitlab_tag_protection.TagProtect", "create_access_level", "maintainer"),
+					resource.TestCheckResourceAttr("gitlab_tag_protection.TagProtect", "allowed_to_create.#", "1"),
+					resource.TestCheckTypeSetElemAttrPair("gitlab_tag_protection.TagProtect", "allowed_to_create.*.deploy_key_id", "gitlab_deploy_key.foo", "id"),
+				),
+			},
+			{
+				SkipFunc:          isRunningInCE,
+				ResourceName:      "gitlab_tag_protection.TagProtect",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
 // lintignore: AT002 // TODO: Resolve this tfproviderlint issue
 func TestAccGitlabTagProtection_import(t *testing.T) {
 	rInt := acctest.RandInt()
@@ -234,3 +263,33 @@ resource "gitlab_tag_protection" "TagProtect" {
 }
 	`, rInt, rInt, postfix)
 }
+
+func testAccGitlabTagProtectionConfigAllowedToCreate(rInt int) string {
+	return fmt.Sprintf(`
+resource "gitlab_project" "foo" {
+  name = "foo-%[1]d"
+  description = "Terraform acceptance tests"
+
+  # So that acceptance tests can be run in a gitlab organization
+  # with no billing
+  visibility_level = "public"
+}
+
+resource "gitlab_deploy_key" "foo" {
+  project  = gitlab_project.foo.id
+  title    = "deployKey-%[1]d"
+  key      = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQCj13ozEBZ0s4el4k6mYqoyIKKKMh9hHY0sAYqSPXs2zGuVFZss1P8TPuwmdXVjHR7TiRXwC49zDrkyWJgiufggYJ1VilOohcMOODwZEJz+E5q4GCfHuh90UEh0nl8B2R0Uoy0LPeg93uZzy0hlHApsxRf/XZJz/1ytkZvCtxdllxfImCVxJReMeRVEqFCTCvy3YuJn0bce7ulcTFRvtgWOpQsr6GDK8YkcCCv2eZthVlrEwy6DEpAKTRiRLGgUj4dPO0MmO4cE2qD4ualY01PhNORJ8Q++I+EtkGt/VALkecwFuBkl18/gy+yxNJHpKc/8WVVinDeFrd/HhiY9yU0d"
+  can_push = true
+}
+
+resource "gitlab_tag_protection" "TagProtect" {
+  project             = gitlab_project.foo.id
+  tag                 = "TagProtect-%[1]d"
+  create_access_level = "maintainer"
+
+  allowed_to_create {
+    deploy_key_id = gitlab_deploy_key.foo.id
+  }
+}
+	`, rInt)
+}
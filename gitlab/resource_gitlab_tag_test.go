@@ -0,0 +1,67 @@
+package gitlab
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func TestAccGitlabTag_basic(t *testing.T) {
+	testAccCheck(t)
+
+	client := testAccNewClient(t)
+	project := testAccCreateProject(t, client)
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabTagDestroy(client, project),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "gitlab_tag" "lightweight" {
+  project = %[1]d
+  name    = "v1.0.0"
+  ref     = %[2]q
+}
+
+resource "gitlab_tag" "annotated" {
+  project = %[1]d
+  name    = "v1.1.0"
+  ref     = %[2]q
+  message = "Release v1.1.0"
+}
+				`, project.ID, project.DefaultBranch),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlab_tag.lightweight", "message", ""),
+					resource.TestCheckResourceAttrSet("gitlab_tag.lightweight", "commit_sha"),
+					resource.TestCheckResourceAttr("gitlab_tag.annotated", "message", "Release v1.1.0"),
+					resource.TestCheckResourceAttrPair("gitlab_tag.lightweight", "commit_sha", "gitlab_tag.annotated", "commit_sha"),
+				),
+			},
+			{
+				ResourceName:            "gitlab_tag.annotated",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"ref"},
+			},
+		},
+	})
+}
+
+func testAccCheckGitlabTagDestroy(client *gitlab.Client, project *gitlab.Project) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, name := range []string{"v1.0.0", "v1.1.0"} {
+			_, _, err := client.Tags.GetTag(project.ID, name)
+			if err == nil {
+				return fmt.Errorf("tag %q still exists", name)
+			}
+			if !is404(err) {
+				return err
+			}
+		}
+		return nil
+	}
+}
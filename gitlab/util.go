@@ -1,8 +1,15 @@
 package gitlab
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io/ioutil"
+	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -79,6 +86,32 @@ var validateURLFunc = func(v interface{}, k string) (s []string, errors []error)
 	return
 }
 
+// validateProtectedBranchNameFunc validates the name of a protected branch, which may contain `*` wildcards.
+// It follows the rules of `git check-ref-format` for the remaining characters.
+var validateProtectedBranchNameFunc = func(v interface{}, k string) (s []string, errors []error) {
+	value := v.(string)
+
+	switch {
+	case value == "":
+		errors = append(errors, fmt.Errorf("%s must not be empty", k))
+	case strings.ContainsAny(value, " ~^:?[\\"):
+		errors = append(errors, fmt.Errorf("%q is not a valid branch name or wildcard for %s: it must not contain spaces or any of `~^:?[\\`", value, k))
+	case strings.Contains(value, "..") || strings.Contains(value, "//") || strings.Contains(value, "@{"):
+		errors = append(errors, fmt.Errorf("%q is not a valid branch name or wildcard for %s: it must not contain `..`, `//` or `@{`", value, k))
+	case strings.HasPrefix(value, "/") || strings.HasPrefix(value, "-") || strings.HasSuffix(value, "/") || strings.HasSuffix(value, ".") || strings.HasSuffix(value, ".lock"):
+		errors = append(errors, fmt.Errorf("%q is not a valid branch name or wildcard for %s: it must not start with `/` or `-` or end with `/`, `.` or `.lock`", value, k))
+	default:
+		for _, r := range value {
+			if r < 0x20 || r == 0x7f {
+				errors = append(errors, fmt.Errorf("%q is not a valid branch name or wildcard for %s: it must not contain control characters", value, k))
+				break
+			}
+		}
+	}
+
+	return
+}
+
 func stringToVisibilityLevel(s string) *gitlab.VisibilityValue {
 	lookup := map[string]gitlab.VisibilityValue{
 		"private":  gitlab.PrivateVisibility,
@@ -280,27 +313,84 @@ func isGitLabVersionLessThan(client *gitlab.Client, version string) func() (bool
 // provided wantVersion. It only checks the major and minor version numbers, not the patch.
 func isGitLabVersionAtLeast(client *gitlab.Client, wantVersion string) func() (bool, error) {
 	return func() (bool, error) {
-		wantMajor, wantMinor, err := parseVersionMajorMinor(wantVersion)
-		if err != nil {
-			return false, fmt.Errorf("failed to parse wanted version %q: %w", wantVersion, err)
-		}
-
-		actualVersion, _, err := client.Version.GetVersion()
+		version, err := getGitlabVersion(context.Background(), client)
 		if err != nil {
 			return false, err
 		}
 
-		actualMajor, actualMinor, err := parseVersionMajorMinor(actualVersion.Version)
-		if err != nil {
-			return false, fmt.Errorf("failed to parse actual version %q: %w", actualVersion.Version, err)
-		}
+		return version.isAtLeast(wantVersion)
+	}
+}
 
-		if actualMajor == wantMajor {
-			return actualMinor >= wantMinor, nil
-		}
+// gitlabVersion is the version and edition of a GitLab instance.
+// Get it once with getGitlabVersion to check for several features.
+type gitlabVersion struct {
+	version      string
+	major, minor int
+	// enterprise is nil if the edition is unknown, because older versions only report it for self-managed
+	// Enterprise Editions with the "-ee" suffix, which GitLab.com doesn't have.
+	enterprise *bool
+}
+
+func getGitlabVersion(ctx context.Context, client *gitlab.Client) (*gitlabVersion, error) {
+	// go-gitlab doesn't accept request options for the version API
+	req, err := client.NewRequest(http.MethodGet, "version", nil, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+
+	// The edition is only reported since GitLab 15.6.
+	v := new(struct {
+		gitlab.Version
+		Enterprise *bool `json:"enterprise"`
+	})
+	if _, err := client.Do(req, v); err != nil {
+		return nil, err
+	}
 
-		return actualMajor > wantMajor, nil
+	enterprise := v.Enterprise
+	if enterprise == nil && strings.HasSuffix(v.Version.Version, "-ee") {
+		enterprise = gitlab.Bool(true)
 	}
+
+	major, minor, err := parseVersionMajorMinor(v.Version.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse actual version %q: %w", v.Version.Version, err)
+	}
+
+	return &gitlabVersion{
+		version:    v.Version.Version,
+		major:      major,
+		minor:      minor,
+		enterprise: enterprise,
+	}, nil
+}
+
+// isEnterpriseEdition reports whether the instance is known to be a GitLab Enterprise Edition.
+func (v *gitlabVersion) isEnterpriseEdition() bool {
+	return v.enterprise != nil && *v.enterprise
+}
+
+// isCommunityEdition reports whether the instance is known to be a GitLab Community Edition.
+// Features of the Enterprise Edition are only refused for it, because on instances of an unknown edition
+// the API reports itself whether the features are available, e.g. depending on the license.
+func (v *gitlabVersion) isCommunityEdition() bool {
+	return v.enterprise != nil && !*v.enterprise
+}
+
+// isAtLeast checks that the version is at least the provided wantVersion.
+// It only checks the major and minor version numbers, not the patch.
+func (v *gitlabVersion) isAtLeast(wantVersion string) (bool, error) {
+	wantMajor, wantMinor, err := parseVersionMajorMinor(wantVersion)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse wanted version %q: %w", wantVersion, err)
+	}
+
+	if v.major == wantMajor {
+		return v.minor >= wantMinor, nil
+	}
+
+	return v.major > wantMajor, nil
 }
 
 func parseVersionMajorMinor(version string) (int, int, error) {
@@ -330,3 +420,61 @@ func is404(err error) bool {
 	}
 	return false
 }
+
+// customizeDiffAvatarHash computes the hash of the local avatar image, so that changes to the image are uploaded.
+func customizeDiffAvatarHash(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("avatar") {
+		if err := d.SetNewComputed("avatar_hash"); err != nil {
+			return err
+		}
+		return d.SetNewComputed("avatar_url")
+	}
+
+	var hash string
+	if avatar := d.Get("avatar").(string); avatar != "" {
+		content, err := ioutil.ReadFile(avatar)
+		if err != nil {
+			return fmt.Errorf("failed to read avatar %q: %w", avatar, err)
+		}
+		sum := sha256.Sum256(content)
+		hash = hex.EncodeToString(sum[:])
+	}
+
+	if hash == d.Get("avatar_hash").(string) {
+		return nil
+	}
+	if err := d.SetNew("avatar_hash", hash); err != nil {
+		return err
+	}
+	return d.SetNewComputed("avatar_url")
+}
+
+// updateGitlabAvatar uploads the local avatar image to the given API path of a project or group.
+// An empty avatar deletes the current avatar.
+func updateGitlabAvatar(ctx context.Context, client *gitlab.Client, apiPath, avatar string) error {
+	if avatar == "" {
+		options := struct {
+			Avatar string `url:"avatar" json:"avatar"`
+		}{}
+
+		req, err := client.NewRequest(http.MethodPut, apiPath, &options, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+		if err != nil {
+			return err
+		}
+		_, err = client.Do(req, nil)
+		return err
+	}
+
+	image, err := os.Open(avatar)
+	if err != nil {
+		return fmt.Errorf("failed to open avatar %q: %w", avatar, err)
+	}
+	defer image.Close()
+
+	req, err := client.UploadRequest(http.MethodPut, apiPath, image, filepath.Base(avatar), gitlab.UploadAvatar, nil, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return err
+	}
+	_, err = client.Do(req, nil)
+	return err
+}